@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mroth/semverdesc/describer"
+)
+
+// auditCmd lists the tags of a repository, reporting any problems with them
+// for describing versions. Exits with status 1 if there are any.
+func auditCmd(args []string) {
+	fs := newFlagSet("git semver-describe audit",
+		"git semver-describe audit [<options>]",
+	)
+	var f describeFlags
+	fs.StringVar(&f.match, "match", "", "only audit tags matching `<pattern>`")
+	fs.StringVar(&f.exclude, "exclude", "", "do not audit tags matching `<pattern>`")
+	fs.StringVar(&f.path, "path", "", "audit repository at `<path>` (default $PWD)")
+	fs.StringVar(&f.component, "component", "", "only audit tags under `<prefix>/`")
+	fs.BoolVar(&f.goModule, "go-module", false, "only audit the tags of the Go module at path")
+	markConfigurable(fs, "match", "exclude", "component", "go-module")
+	quiet := fs.Bool("quiet", false, "only report problems")
+	fs.Parse(args)
+	configure(fs, f.path)
+
+	opts := describer.Options{
+		MatchPattern:   f.match,
+		ExcludePattern: f.exclude,
+		TagPrefix:      tagPrefix(f.component),
+		GoModule:       f.goModule,
+	}
+	report, err := describer.Audit(f.path, opts)
+	if err != nil {
+		exitWithError(err)
+	}
+	if !*quiet {
+		printAuditTags(report)
+		fmt.Println()
+	}
+	if len(report.Findings) == 0 {
+		fmt.Printf("no problems found with %d tag(s).\n", len(report.Tags))
+		return
+	}
+	fmt.Printf("%d problem(s) found with %d tag(s):\n\n", len(report.Findings), len(report.Tags))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, finding := range report.Findings {
+		fmt.Fprintf(w, "  %v\t%v\n", finding.Problem, finding.Message)
+	}
+	w.Flush()
+	os.Exit(1)
+}
+
+// printAuditTags prints the tags of an audit as a table, along with the
+// problems each of them is involved in. Tags which another is out of order
+// with are not themselves a problem, so aren't marked.
+func printAuditTags(report *describer.AuditReport) {
+	problems := make(map[string][]string)
+	for _, finding := range report.Findings {
+		tags := finding.Tags
+		if finding.Problem == describer.ProblemOutOfOrder {
+			tags = tags[:1]
+		}
+		for _, tag := range tags {
+			problems[tag] = append(problems[tag], finding.Problem.String())
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tTYPE\tCOMMIT\tPROBLEMS")
+	for _, t := range report.Tags {
+		kind := "lightweight"
+		if t.Annotated {
+			kind = "annotated"
+		}
+		commit := "-"
+		if len(t.HashStr) >= 7 {
+			commit = t.HashStr[:7]
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", t.Name, kind, commit, strings.Join(problems[t.Name], ", "))
+	}
+	w.Flush()
+}
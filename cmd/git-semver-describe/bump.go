@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc/describer"
+	"github.com/mroth/semverdesc/semver"
+	"github.com/spf13/pflag"
+)
+
+// bumpFlags are the flags of bumpCmd. Only the shared describe flags may be
+// configured, never those creating a tag.
+type bumpFlags struct {
+	describeFlags
+	pre       string
+	createTag bool
+	sign      bool
+	message   string
+	dryRun    bool
+}
+
+// newBumpFlagSet returns the flag set of bumpCmd, along with its flags.
+func newBumpFlagSet() (*pflag.FlagSet, *bumpFlags) {
+	fs := newFlagSet("git semver-describe bump",
+		"git semver-describe bump [<options>] (major|minor|patch|prerelease) [<commit-ish>]",
+	)
+	var f bumpFlags
+	f.addGitFlags(fs)
+	fs.StringVar(&f.pre, "pre", "", "start a pre-release with `<id>`, e.g. rc")
+	fs.BoolVar(&f.createTag, "create-tag", false, "create an annotated tag for the next version")
+	fs.BoolVar(&f.sign, "sign", false, "create a GPG-signed tag (implies --create-tag)")
+	fs.StringVar(&f.message, "message", "", "use `<msg>` as the tag message (default \"Release <tag>\")")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "show the tag command rather than running it")
+	f.addExtraFlags(fs)
+	return fs, &f
+}
+
+// bumpCmd describes a commit-ish, then prints the version following its tag,
+// optionally creating a tag for it.
+func bumpCmd(args []string) {
+	fs, f := newBumpFlagSet()
+	fs.Parse(args)
+	configure(fs, f.path)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	part, err := semver.ParsePart(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	commitish := fs.Arg(1)
+	if (f.message != "" || f.dryRun) && !f.createTag && !f.sign {
+		log.Fatal("--message and --dry-run require --create-tag")
+	}
+
+	opts := f.options()
+	d, err := describer.Describe(f.path, commitish, opts)
+	if err != nil {
+		exitWithError(err)
+	}
+	current, err := semver.Parse(d.TagName)
+	if err != nil {
+		log.Fatalf("%v is not a SemVer tag, so can't be bumped", d.TagName)
+	}
+	next, err := semver.Bump(current, part, f.pre)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.TrimPrefix(next.String(), f.trimPrefix))
+
+	if !f.createTag && !f.sign {
+		return
+	}
+	prefix, err := describer.ResolveTagPrefix(f.path, opts)
+	if err != nil {
+		exitWithError(err)
+	}
+	tag := prefix + next.String()
+	if f.message == "" {
+		f.message = "Release " + tag
+	}
+	tagArgs := tagCommand(tag, commitish, f.message, f.sign)
+	if f.dryRun {
+		fmt.Fprintln(os.Stderr, "would run: git "+shellJoin(tagArgs))
+		return
+	}
+	if commitish == "" && (d.Dirty || d.Broken) {
+		fmt.Fprintln(os.Stderr, "warning: working tree has local modifications which will not be part of", tag)
+	}
+	cmd := exec.Command("git", tagArgs...)
+	cmd.Dir = f.path
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exiterr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exiterr.ExitCode())
+		}
+		log.Fatal(err)
+	}
+}
+
+// tagCommand returns the git arguments creating an annotated (or signed) tag
+// named tag with message at commitish, or HEAD if it is empty.
+func tagCommand(tag, commitish, message string, sign bool) []string {
+	args := []string{"tag", "--annotate"}
+	if sign {
+		args[1] = "--sign"
+	}
+	args = append(args, "--message", message, tag)
+	if commitish != "" {
+		args = append(args, commitish)
+	}
+	return args
+}
+
+// shellJoin joins args for display as a command line, quoting any containing
+// whitespace or quotes.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"") {
+			a = strconv.Quote(a)
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBumpFlags_configured(t *testing.T) {
+	repo := configRepo(t, "[semverdesc]\n\ttags = true\n\tcreate-tag = true\n\tsign = true\n"+
+		"\tdry-run = true\n\tmessage = configured\n\tpre = rc\n")
+	defer os.RemoveAll(repo)
+
+	fs, f := newBumpFlagSet()
+	if err := fs.Parse([]string{"patch"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := applyConfig(fs, repo); err != nil {
+		t.Fatal(err)
+	}
+	if !f.tags {
+		t.Error("tags not configured")
+	}
+	if f.createTag || f.sign || f.dryRun || f.message != "" || f.pre != "" {
+		t.Errorf("bump flags configured: create-tag=%v sign=%v dry-run=%v message=%q pre=%q",
+			f.createTag, f.sign, f.dryRun, f.message, f.pre)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Defaults for flags can be configured with keys in the configSection of git
+// config, e.g. `git config semverdesc.tags true`, or committed to the project
+// in a configFile at the root of the repository, which uses the same syntax:
+//
+//	[semverdesc]
+//		tags = true
+//		match = v*
+//		trim = v
+//		dirty
+//
+// Each key is the name of a flag supplying a default for how commits are
+// described or formatted, as marked by markConfigurable. Flags which may be repeated accept multiple
+// values for the key, and flags with an optional value (such as --dirty) use
+// their default value when the key is set to true. Flags set on the command
+// line override git config, which in turn overrides the configFile.
+const (
+	configSection = "semverdesc"
+	configFile    = ".semverdesc"
+)
+
+// Sources of flag values, as shown by --print-config.
+const (
+	sourceDefault     = "default"
+	sourceConfigFile  = configFile
+	sourceGitConfig   = "git config"
+	sourceCommandLine = "command line"
+)
+
+// configurableAnnotation marks the flags which may be configured.
+const configurableAnnotation = "semverdesc-configurable"
+
+// markConfigurable marks the named flags of fs as configurable. Only flags
+// supplying defaults for describer.Options and semverdesc.FormatOptions should
+// be, never those which trigger actions or change the output mode, so that the
+// configuration of a repository can't make a command do more than was asked.
+func markConfigurable(fs *pflag.FlagSet, names ...string) {
+	for _, name := range names {
+		if err := fs.SetAnnotation(name, configurableAnnotation, []string{"true"}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// isConfigurable reports whether a flag was marked by markConfigurable.
+func isConfigurable(fl *pflag.Flag) bool {
+	_, ok := fl.Annotations[configurableAnnotation]
+	return ok
+}
+
+// configValue are the values configured for a key, and where from.
+type configValue struct {
+	values []string
+	source string
+}
+
+// applyConfig sets each flag of fs which was not set on the command line from
+// the configuration of the repository located at path, returning the source of
+// the value of each flag.
+func applyConfig(fs *pflag.FlagSet, path string) (map[string]string, error) {
+	config, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+	var applyErr error
+	fs.VisitAll(func(fl *pflag.Flag) {
+		switch c, ok := config[fl.Name]; {
+		case fl.Changed:
+			sources[fl.Name] = sourceCommandLine
+		case ok && isConfigurable(fl) && applyErr == nil:
+			sources[fl.Name] = c.source
+			if err := setFromConfig(fl, c.values); err != nil {
+				applyErr = fmt.Errorf("invalid %v.%v in %v: %v", configSection, fl.Name, c.source, err)
+			}
+		default:
+			sources[fl.Name] = sourceDefault
+		}
+	})
+	return sources, applyErr
+}
+
+// configure applies the configuration of the repository located at path to
+// the flags of fs after parsing, exiting if it is invalid.
+func configure(fs *pflag.FlagSet, path string) map[string]string {
+	sources, err := applyConfig(fs, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return sources
+}
+
+// setFromConfig sets the value of a flag from its configured values, without
+// marking it as changed on the command line.
+func setFromConfig(fl *pflag.Flag, values []string) error {
+	if sv, ok := fl.Value.(pflag.SliceValue); ok {
+		return sv.Replace(values)
+	}
+	value := values[len(values)-1]
+	switch {
+	case fl.Value.Type() == "bool":
+		if b, ok := parseConfigBool(value); ok {
+			value = fmt.Sprint(b)
+		}
+	case fl.NoOptDefVal != "":
+		if b, ok := parseConfigBool(value); ok {
+			if !b {
+				return nil
+			}
+			value = fl.NoOptDefVal
+		}
+	}
+	return fl.Value.Set(value)
+}
+
+// parseConfigBool parses a boolean value as git config does.
+func parseConfigBool(value string) (b, ok bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0", "":
+		return false, true
+	}
+	return false, false
+}
+
+// readConfig reads the configured values for the repository located at path,
+// keyed by flag name. If path is not within a repository, only the global git
+// config is read.
+func readConfig(path string) (map[string]configValue, error) {
+	config := make(map[string]configValue)
+	if top, ok := topLevel(path); ok {
+		file := filepath.Join(top, configFile)
+		if _, err := os.Stat(file); err == nil {
+			entries, err := readConfigEntries(path, "--file", file)
+			if err != nil {
+				return nil, err
+			}
+			for k, vs := range entries {
+				config[k] = configValue{values: vs, source: sourceConfigFile}
+			}
+		}
+	}
+
+	entries, err := readConfigEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range entries {
+		config[k] = configValue{values: vs, source: sourceGitConfig}
+	}
+	return config, nil
+}
+
+// topLevel returns the root of the working tree containing path, if any.
+// Rather than running git, it looks for the .git directory (or file, for
+// linked worktrees and submodules) as git does, unless the environment
+// overrides where git finds them.
+func topLevel(path string) (string, bool) {
+	if os.Getenv("GIT_DIR") != "" || os.Getenv("GIT_WORK_TREE") != "" {
+		output, err := gitOutput(path, "rev-parse", "--show-toplevel")
+		return strings.TrimSpace(string(output)), err == nil
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// readConfigEntries returns the values of all keys in the configSection, keyed
+// by name without the section, from git config with the given extra args.
+func readConfigEntries(path string, args ...string) (map[string][]string, error) {
+	args = append([]string{"config", "-z"}, args...)
+	args = append(args, "--get-regexp", `^`+configSection+`\.`)
+	output, err := gitOutput(path, args...)
+	if exiterr, ok := err.(*exec.ExitError); ok && exiterr.ExitCode() == 1 {
+		// no matching keys
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return parseConfigEntries(output), nil
+}
+
+// parseConfigEntries parses the output of `git config -z --get-regexp`, in
+// which each entry is a key and value separated by a newline and terminated
+// by a NUL, or a key alone for an implicitly true boolean.
+func parseConfigEntries(output []byte) map[string][]string {
+	entries := make(map[string][]string)
+	for _, entry := range bytes.Split(output, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		key, value := string(entry), "true"
+		if i := bytes.IndexByte(entry, '\n'); i != -1 {
+			key, value = string(entry[:i]), string(entry[i+1:])
+		}
+		key = strings.TrimPrefix(key, configSection+".")
+		entries[key] = append(entries[key], value)
+	}
+	return entries
+}
+
+// printConfig prints the effective value of each configurable flag in git
+// config syntax, along with its source.
+func printConfig(fs *pflag.FlagSet, sources map[string]string) {
+	fmt.Printf("[%v]\n", configSection)
+	fs.VisitAll(func(fl *pflag.Flag) {
+		if fl.Hidden || !isConfigurable(fl) {
+			return
+		}
+		values := []string{fl.Value.String()}
+		if sv, ok := fl.Value.(pflag.SliceValue); ok {
+			values = sv.GetSlice()
+		}
+		for _, v := range values {
+			fmt.Printf("\t%v = %q\t# %v\n", fl.Name, v, sources[fl.Name])
+		}
+	})
+}
+
+// gitOutput runs a git command in the directory path, returning its output.
+func gitOutput(path string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	return cmd.Output()
+}
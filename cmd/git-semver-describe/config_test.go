@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// configRepo creates a git repository in a temporary directory with a
+// configFile of the given contents, returning its path, which the caller
+// should remove when done. The test is skipped if git is unavailable.
+func configRepo(t *testing.T, config string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir, err := ioutil.TempDir("", "semverdesc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "init", "--quiet")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, configFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestApplyConfig(t *testing.T) {
+	repo := configRepo(t, "[semverdesc]\n\ttags = true\n\tmatch = v*\n\tjson = true\n")
+	defer os.RemoveAll(repo)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	var f describeFlags
+	f.addGitFlags(fs)
+	jsonOutput := fs.Bool("json", false, "output results as JSON")
+	if err := fs.Parse([]string{"--match", "release-*"}); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := applyConfig(fs, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !f.tags || sources["tags"] != sourceConfigFile {
+		t.Errorf("tags = %v from %v, want true from %v", f.tags, sources["tags"], sourceConfigFile)
+	}
+	if f.match != "release-*" || sources["match"] != sourceCommandLine {
+		t.Errorf("match = %v from %v, want release-* from %v", f.match, sources["match"], sourceCommandLine)
+	}
+	if *jsonOutput || sources["json"] != sourceDefault {
+		t.Errorf("json = %v from %v, want false from %v", *jsonOutput, sources["json"], sourceDefault)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/mroth/semverdesc/describer"
+)
+
+// explainCmd describes a commit-ish, then shows the candidate tags git
+// considered and why the tag in the results was chosen.
+func explainCmd(args []string) {
+	fs := newFlagSet("git semver-describe explain",
+		"git semver-describe explain [<options>] [<commit-ish>]",
+	)
+	var f describeFlags
+	f.addGitFlags(fs)
+	f.addExtraFlags(fs)
+	fs.Parse(args)
+	configure(fs, f.path)
+
+	e, err := describer.Explain(f.path, fs.Arg(0), f.options())
+	if err != nil {
+		exitWithError(err)
+	}
+	fmt.Println(f.format(e.Results, f.formatOptions()))
+	fmt.Println()
+	printExplanation(e)
+}
+
+// printExplanation prints the candidates of an explanation as a table, marking
+// the chosen tag, followed by the reasoning for choosing it.
+func printExplanation(e *describer.Explanation) {
+	tag := e.Results.TagName
+	if e.ExactMatch {
+		fmt.Printf("%d tag(s) point directly at the commit, so git did not search its history:\n\n",
+			len(e.Candidates))
+	} else {
+		fmt.Printf("git found %d candidate tag(s), traversing %d commits:\n\n",
+			len(e.Candidates), e.Traversed)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  DEPTH\tTYPE\tTAG")
+	for _, c := range e.Candidates {
+		mark := " "
+		if c.TagName == tag {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%v %d\t%v\t%v\n", mark, c.Depth, c.Type, c.TagName)
+	}
+	w.Flush()
+	fmt.Println()
+
+	switch {
+	case e.Selection == describer.SelectHighestPrecedence:
+		fmt.Printf("%v has the highest SemVer precedence of the nearest candidates (--select=%v).\n",
+			tag, e.Selection)
+	case e.ExactMatch:
+		fmt.Printf("git chose %v, preferring annotated tags and then the most recent tag date (use --select=semver to prefer SemVer precedence).\n", tag)
+	default:
+		fmt.Printf("git chose %v as the nearest candidate, breaking ties by the most recent tag date.\n", tag)
+	}
+	if e.GaveUp {
+		fmt.Println("git stopped searching once it had enough candidates, so nearer tags may have been missed (raise --candidates to consider more).")
+	}
+}
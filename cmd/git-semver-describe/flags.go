@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/describer"
+	"github.com/spf13/pflag"
+)
+
+// describeFlags are the flags shared by all commands which perform a describe
+// operation, registered in groups so each command can order them in its help.
+type describeFlags struct {
+	// flags compatible with git-describe...
+	all         bool
+	tags        bool
+	long        bool
+	firstParent bool
+	abbrev      uint
+	exactMatch  bool
+	candidates  uint
+	match       string
+	exclude     string
+
+	// flags unique to us...
+	path       string
+	component  string
+	scope      []string
+	semverOnly bool
+	goModule   bool
+	selection  string
+	shallow    string
+	fallback   string
+	trimPrefix string
+	legacy     bool
+}
+
+// addGitFlags registers the flags compatible with git-describe.
+func (f *describeFlags) addGitFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&f.all, "all", false, "use any ref")
+	fs.BoolVar(&f.tags, "tags", false, "use any tag, even unannotated")
+	fs.BoolVar(&f.long, "long", false, "always use long format")
+	fs.BoolVar(&f.firstParent, "first-parent", false, "only follow first parent")
+	fs.UintVar(&f.abbrev, "abbrev", semverdesc.DefaultFormatAbbrev, "use `<n>` digits to display SHA-1s")
+	fs.BoolVar(&f.exactMatch, "exact-match", false, "only output exact matches")
+	fs.UintVar(&f.candidates, "candidates", describer.DefaultCandidatesOption, "consider `<n>` most recent tags")
+	fs.StringVar(&f.match, "match", "", "only consider tags matching `<pattern>`")
+	fs.StringVar(&f.exclude, "exclude", "", "do not consider tags matching `<pattern>`")
+	markConfigurable(fs, "all", "tags", "long", "first-parent", "abbrev", "exact-match",
+		"candidates", "match", "exclude")
+}
+
+// addExtraFlags registers the flags unique to semver-describe.
+func (f *describeFlags) addExtraFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.path, "path", "", "describe repository at `<path>` (default $PWD)")
+	fs.StringVar(&f.component, "component", "", "only consider tags under `<prefix>/`, stripping it from results")
+	fs.StringArrayVar(&f.scope, "scope", nil, "only count commits touching `<pathspec>` (repeatable)")
+	fs.BoolVar(&f.semverOnly, "semver-only", false, "only consider tags which are valid SemVer")
+	fs.BoolVar(&f.goModule, "go-module", false, "follow Go module tagging conventions for go.mod at path")
+	fs.StringVar(&f.selection, "select", describer.SelectGit.String(), "choose between candidate tags by `<policy>` (git|semver)")
+	fs.StringVar(&f.shallow, "shallow", describer.ShallowApproximate.String(), "handle shallow clones by `<policy>` (approximate|error|fallback)")
+	fs.StringVar(&f.fallback, "fallback-version", "", "use `<version>` when a shallow clone can't be described")
+	fs.StringVar(&f.trimPrefix, "trim", "", "trim `<prefix>` from results")
+	fs.BoolVar(&f.legacy, "legacy", false, "format results like normal git describe")
+	markConfigurable(fs, "component", "scope", "semver-only", "go-module", "select",
+		"shallow", "fallback-version", "trim", "legacy")
+}
+
+// options returns the describer.Options set by the flags, exiting if any of
+// them are invalid or contradictory.
+func (f *describeFlags) options() describer.Options {
+	selection, err := describer.ParseSelectionPolicy(f.selection)
+	if err != nil {
+		log.Fatal(err)
+	}
+	shallow, err := describer.ParseShallowPolicy(f.shallow)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := describer.Options{
+		Tags:            f.tags,
+		Candidates:      f.candidates,
+		MatchPattern:    f.match,
+		ExcludePattern:  f.exclude,
+		All:             f.all,
+		ExactMatch:      f.exactMatch,
+		FirstParent:     f.firstParent,
+		Selection:       selection,
+		SemverOnly:      f.semverOnly,
+		TagPrefix:       tagPrefix(f.component),
+		Paths:           f.scope,
+		GoModule:        f.goModule,
+		Shallow:         shallow,
+		FallbackVersion: f.fallback,
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	return opts
+}
+
+// tagPrefix returns the describer.Options TagPrefix for a component, which is
+// a directory-like path with or without a trailing slash.
+func tagPrefix(component string) string {
+	if component == "" || strings.HasSuffix(component, "/") {
+		return component
+	}
+	return component + "/"
+}
+
+// formatOptions returns the semverdesc.FormatOptions set by the flags.
+func (f *describeFlags) formatOptions() semverdesc.FormatOptions {
+	return semverdesc.FormatOptions{
+		Abbrev: f.abbrev,
+		Long:   f.long,
+	}
+}
+
+// formatter is implemented by all of the semverdesc result types.
+type formatter interface {
+	Format(opts semverdesc.FormatOptions) string
+	FormatLegacy(opts semverdesc.FormatOptions) string
+}
+
+// format formats the results according to the flags.
+func (f *describeFlags) format(d formatter, formatOpts semverdesc.FormatOptions) string {
+	// the prefix trimming option is handled locally rather than in the library
+	// since it is a convenience function for cross-platform CLI handiness, but
+	// is not necessary when using as a library since you can just handle with
+	// stdlib directly.
+	var formattedResults string
+	if f.legacy {
+		formattedResults = d.FormatLegacy(formatOpts)
+	} else {
+		formattedResults = d.Format(formatOpts)
+	}
+	return strings.TrimPrefix(formattedResults, f.trimPrefix)
+}
+
+// jsonReport is the schema of the --json output: a semverdesc.Report along
+// with the describer.Options the results were described with.
+type jsonReport struct {
+	*semverdesc.Report
+	Options describer.Options `json:"options"`
+}
+
+// formatJSON returns the JSON report for the results, with any --trim prefix
+// trimmed from the formatted strings as for format.
+func (f *describeFlags) formatJSON(d *semverdesc.DescribeResults, opts describer.Options, formatOpts semverdesc.FormatOptions) string {
+	return marshalJSON(f.jsonReport(d, opts, formatOpts))
+}
+
+// jsonReport returns the jsonReport for the results, with any --trim prefix
+// trimmed from the formatted strings as for format.
+func (f *describeFlags) jsonReport(d *semverdesc.DescribeResults, opts describer.Options, formatOpts semverdesc.FormatOptions) jsonReport {
+	// lists are always arrays in the schema, never null
+	if opts.Paths == nil {
+		opts.Paths = []string{}
+	}
+	if opts.DirtyIgnore == nil {
+		opts.DirtyIgnore = []string{}
+	}
+	return jsonReport{Report: f.report(d, formatOpts), Options: opts}
+}
+
+// report returns the semverdesc.Report for the results, with any --trim
+// prefix trimmed from the formatted strings as for format.
+func (f *describeFlags) report(d *semverdesc.DescribeResults, formatOpts semverdesc.FormatOptions) *semverdesc.Report {
+	report := semverdesc.NewReport(d, formatOpts)
+	report.Version = strings.TrimPrefix(report.Version, f.trimPrefix)
+	report.Legacy = strings.TrimPrefix(report.Legacy, f.trimPrefix)
+	return report
+}
+
+// jsonSubmoduleReport is the schema of the --json output with
+// --recurse-submodules: the jsonReport of the superproject, along with the
+// results of each of its submodules.
+type jsonSubmoduleReport struct {
+	Superproject jsonReport      `json:"superproject"`
+	Submodules   []jsonSubmodule `json:"submodules"`
+}
+
+// jsonSubmodule is the schema of the results of a single submodule in a
+// jsonSubmoduleReport, where Report is null and Error is set if it could not
+// be described.
+type jsonSubmodule struct {
+	Path     string             `json:"path"`
+	Modified bool               `json:"modified"`
+	Report   *semverdesc.Report `json:"report"`
+	Error    string             `json:"error"`
+}
+
+// formatSubmodulesJSON returns the JSON report for the results of describing
+// submodules, with any --trim prefix trimmed from the formatted strings as for
+// format.
+func (f *describeFlags) formatSubmodulesJSON(r *describer.SubmoduleReport, opts describer.Options, formatOpts semverdesc.FormatOptions) string {
+	report := jsonSubmoduleReport{
+		Superproject: f.jsonReport(r.Superproject, opts, formatOpts),
+		Submodules:   []jsonSubmodule{},
+	}
+	for _, s := range r.Submodules {
+		sub := jsonSubmodule{Path: s.Path, Modified: s.Modified}
+		if s.Err != nil {
+			sub.Error = errorMessage(s.Err)
+		} else {
+			sub.Report = f.report(s.Results, formatOpts)
+		}
+		report.Submodules = append(report.Submodules, sub)
+	}
+	return marshalJSON(report)
+}
+
+// marshalJSON returns v encoded as JSON, which can only fail for a schema
+// which is not encodable at all.
+func marshalJSON(v interface{}) string {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(encoded)
+}
+
+// newFlagSet returns a FlagSet for a command, with the given usage lines
+// printed before the flag defaults in its help.
+func newFlagSet(name string, usage ...string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ExitOnError)
+	fs.SortFlags = false
+	fs.Usage = func() {
+		for i, u := range usage {
+			if i == 0 {
+				fmt.Fprintf(os.Stderr, "usage: %s\n", u)
+			} else {
+				fmt.Fprintf(os.Stderr, "   or: %s\n", u)
+			}
+		}
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	return fs
+}
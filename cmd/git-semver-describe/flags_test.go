@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/describer"
+)
+
+func TestFormatSubmodulesJSON(t *testing.T) {
+	f := describeFlags{trimPrefix: "v"}
+	report := &describer.SubmoduleReport{
+		Superproject: &semverdesc.DescribeResults{
+			TagName:  "v1.2.0",
+			Distance: 3,
+			HashStr:  "29ae02f0c7b5ab15d41e63058c1c44fff905e81a",
+			Dirty:    true,
+		},
+		Submodules: []describer.SubmoduleResults{
+			{
+				Path:     "libs/core",
+				Modified: true,
+				Results: &semverdesc.DescribeResults{
+					TagName:  "v0.3.1",
+					Distance: 1,
+					HashStr:  "ea2f6b8d2c45ab15d41e63058c1c44fff905e81b",
+				},
+			},
+			{Path: "libs/untagged", Err: errors.New("no names found")},
+		},
+	}
+	formatOpts := semverdesc.FormatOptions{Abbrev: 7, DirtyMark: "-dirty"}
+
+	var got struct {
+		Superproject struct {
+			Version string `json:"version"`
+			Dirty   bool   `json:"dirty"`
+			Options *struct {
+				Paths []string `json:"paths"`
+			} `json:"options"`
+		} `json:"superproject"`
+		Submodules []struct {
+			Path     string `json:"path"`
+			Modified bool   `json:"modified"`
+			Report   *struct {
+				Version string `json:"version"`
+			} `json:"report"`
+			Error string `json:"error"`
+		} `json:"submodules"`
+	}
+	encoded := f.formatSubmodulesJSON(report, describer.Options{}, formatOpts)
+	if err := json.Unmarshal([]byte(encoded), &got); err != nil {
+		t.Fatalf("formatSubmodulesJSON() = %s, error %v", encoded, err)
+	}
+
+	super := got.Superproject
+	if super.Version != "1.2.0+3.g29ae02f-dirty" || !super.Dirty {
+		t.Errorf("superproject = %+v, want version 1.2.0+3.g29ae02f-dirty and dirty", super)
+	}
+	if super.Options == nil || super.Options.Paths == nil {
+		t.Errorf("superproject options = %+v, want paths to be an array", super.Options)
+	}
+	if len(got.Submodules) != 2 {
+		t.Fatalf("submodules = %+v, want 2", got.Submodules)
+	}
+	core := got.Submodules[0]
+	if core.Path != "libs/core" || !core.Modified || core.Report == nil ||
+		core.Report.Version != "0.3.1+1.gea2f6b8" || core.Error != "" {
+		t.Errorf("submodules[0] = %+v, want libs/core modified at 0.3.1+1.gea2f6b8", core)
+	}
+	untagged := got.Submodules[1]
+	if untagged.Path != "libs/untagged" || untagged.Report != nil || untagged.Error != "no names found" {
+		t.Errorf("submodules[1] = %+v, want libs/untagged with a null report and its error", untagged)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/describer"
+)
+
+// defaultLogFormat is the default --format of the log command.
+const defaultLogFormat = "%h %v %s"
+
+// logCmd prints the commits in a revision range annotated with their semver
+// describe.
+func logCmd(args []string) {
+	fs := newFlagSet("git semver-describe log",
+		"git semver-describe log [<options>] [<revision-range>]",
+	)
+	usage := fs.Usage
+	fs.Usage = func() {
+		usage()
+		fmt.Fprintf(os.Stderr, "\nformat placeholders: %%H (commit hash), %%h (abbreviated commit hash),\n")
+		fmt.Fprintf(os.Stderr, "%%v (semver describe), %%s (subject), %%%% (a literal %%)\n")
+	}
+	var f describeFlags
+	f.addGitFlags(fs)
+	format := fs.String("format", defaultLogFormat, "print each commit using `<format>`")
+	f.addExtraFlags(fs)
+	fs.Parse(args)
+	configure(fs, f.path)
+
+	entries, err := describer.Log(f.path, fs.Arg(0), f.options())
+	if err != nil {
+		exitWithError(err)
+	}
+	formatOpts := f.formatOptions()
+	for _, e := range entries {
+		fmt.Println(formatLogEntry(*format, e, &f, formatOpts))
+	}
+}
+
+// formatLogEntry expands the placeholders of a log --format for an entry. A
+// commit which could not be described (e.g. it predates any tag) expands %v
+// to "-".
+func formatLogEntry(format string, e describer.LogEntry, f *describeFlags, formatOpts semverdesc.FormatOptions) string {
+	abbrev := int(formatOpts.Abbrev)
+	if abbrev == 0 {
+		abbrev = int(semverdesc.DefaultFormatAbbrev)
+	}
+	if abbrev > len(e.HashStr) {
+		abbrev = len(e.HashStr)
+	}
+	version := "-"
+	if e.Err == nil {
+		version = f.format(e.Results, formatOpts)
+	}
+
+	r := strings.NewReplacer(
+		"%%", "%",
+		"%H", e.HashStr,
+		"%h", e.HashStr[:abbrev],
+		"%v", version,
+		"%s", e.Subject,
+	)
+	return r.Replace(format)
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"log"
@@ -18,82 +19,305 @@ var (
 	buildVersion = "0.0.0-dev"
 )
 
-var (
-	// flags compatible with git-describe...
-	all         = pflag.Bool("all", false, "use any ref")
-	tags        = pflag.Bool("tags", false, "use any tag, even unannotated")
-	long        = pflag.Bool("long", false, "always use long format")
-	firstParent = pflag.Bool("first-parent", false, "only follow first parent")
-	abbrev      = pflag.Uint("abbrev", semverdesc.DefaultFormatAbbrev, "use `<n>` digits to display SHA-1s")
-	exactMatch  = pflag.Bool("exact-match", false, "only output exact matches")
-	candidates  = pflag.Uint("candidates", describer.DefaultCandidatesOption, "consider `<n>` most recent tags")
-	match       = pflag.String("match", "", "only consider tags matching `<pattern>`")
-	exclude     = pflag.String("exclude", "", "do not consider tags matching `<pattern>`")
-	dirty       = pflag.String("dirty", "", "append `<mark>` on dirty working tree")
-
-	// flags unique to us...
-	path       = pflag.String("path", "", "describe repository at `<path>` (default $PWD)")
-	trimPrefix = pflag.String("trim", "", "trim `<prefix>` from results")
-	legacy     = pflag.Bool("legacy", false, "format results like normal git describe")
-	version    = pflag.Bool("version", false, "display version information and exit")
-)
+// subcommands are the commands available in addition to the default describe,
+// keyed by the name which must be given as the first argument.
+var subcommands = map[string]func(args []string){
+	"log":     logCmd,
+	"explain": explainCmd,
+	"bump":    bumpCmd,
+	"next":    nextCmd,
+	"audit":   auditCmd,
+}
+
+// subcommandFlag names a subcommand explicitly, for when a revision has the
+// same name.
+const subcommandFlag = "--subcommand="
 
 func main() {
 	pflag.ErrHelp = errors.New("")
-	pflag.CommandLine.SortFlags = false
-	pflag.CommandLine.Lookup("dirty").NoOptDefVal = "-dirty"
-	pflag.CommandLine.MarkHidden("version")
-	pflag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "usage: git semver-describe [<options>] [<commit-ish>]\n")
-		fmt.Fprintf(os.Stderr, "   or: git semver-describe [<options>] --dirty\n\n")
-		pflag.PrintDefaults()
+	if cmd, args, ok := subcommand(os.Args[1:], isRevision); ok {
+		cmd(args)
+		return
+	}
+	describeCmd(os.Args[1:])
+}
+
+// subcommand returns the subcommand named by the first of args, if any, along
+// with the arguments for it. So that describing a commit-ish works as it does
+// for git describe, a name which isRevision is described instead (with a
+// warning), unless it is given explicitly as --subcommand=<name>.
+func subcommand(args []string, isRevision func(string) bool) (func([]string), []string, bool) {
+	if len(args) == 0 {
+		return nil, nil, false
+	}
+	if strings.HasPrefix(args[0], subcommandFlag) {
+		name := strings.TrimPrefix(args[0], subcommandFlag)
+		cmd, ok := subcommands[name]
+		if !ok {
+			log.Fatalf("unknown subcommand %q", name)
+		}
+		return cmd, args[1:], true
+	}
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return nil, nil, false
 	}
-	pflag.Parse()
+	if isRevision(args[0]) {
+		fmt.Fprintf(os.Stderr, "warning: %[1]v is a revision, so describing it; use %[2]v%[1]v to run the subcommand\n",
+			args[0], subcommandFlag)
+		return nil, nil, false
+	}
+	return cmd, args[1:], true
+}
+
+// isRevision reports whether name resolves to a commit in the repository of
+// the working directory.
+func isRevision(name string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", name+"^{commit}").Run() == nil
+}
+
+// describeCmd is the default command, a drop-in replacement for git describe.
+func describeCmd(args []string) {
+	fs := newFlagSet("git semver-describe",
+		"git semver-describe [<options>] [<commit-ish>]",
+		"git semver-describe [<options>] --dirty",
+		"git semver-describe [<options>] --broken",
+		"git semver-describe [<options>] --stdin",
+		"git semver-describe log [<options>] [<revision-range>]",
+		"git semver-describe explain [<options>] [<commit-ish>]",
+		"git semver-describe bump [<options>] (major|minor|patch|prerelease) [<commit-ish>]",
+		"git semver-describe next [<options>] [<commit-ish>]",
+		"git semver-describe audit [<options>]",
+	)
+	var f describeFlags
+	f.addGitFlags(fs)
+	dirty := fs.String("dirty", "", "append `<mark>` on dirty working tree")
+	broken := fs.String("broken", "", "append `<mark>` on broken working tree")
+	contains := fs.Bool("contains", false, "find the tag that comes after the commit")
+	stdin := fs.Bool("stdin", false, "read commit-ishes to describe from stdin")
+	recurseSubmodules := fs.Bool("recurse-submodules", false, "also describe every initialized submodule")
+	dirtySubmodules := fs.Bool("dirty-submodules", false, "treat submodules off their recorded commit as dirty")
+	dirtyUntracked := fs.Bool("dirty-untracked", false, "treat untracked files as dirty")
+	dirtyIgnore := fs.StringArray("dirty-ignore", nil, "ignore changes matching `<pathspec>` when checking dirty")
+	dirtyIgnoreSubmodules := fs.Bool("dirty-ignore-submodules", false, "ignore submodule changes when checking dirty")
+	dirtyHash := fs.Uint("dirty-hash", 0, "append `<n>` digits of a hash of the changes to the dirty mark")
+	require := fs.String("require", "", "fail unless results are a `<mode>` (release|prerelease|exact|clean)")
+	jsonOutput := fs.Bool("json", false, "output results as JSON")
+	noCache := fs.Bool("no-cache", false, "do not use or update the results cache in .git")
+	f.addExtraFlags(fs)
+	printConfigFlag := fs.Bool("print-config", false, "show the effective configuration and exit")
+	version := fs.Bool("version", false, "display version information and exit")
+	markConfigurable(fs, "dirty", "broken", "dirty-submodules", "dirty-untracked", "dirty-ignore",
+		"dirty-ignore-submodules", "dirty-hash", "no-cache")
+	fs.Lookup("dirty").NoOptDefVal = "-dirty"
+	fs.Lookup("broken").NoOptDefVal = "-broken"
+	fs.Lookup("dirty-hash").NoOptDefVal = "6"
+	fs.MarkHidden("version")
+	fs.Parse(args)
 
 	if *version {
 		fmt.Println("git-semver-describe version", buildVersion)
 		os.Exit(0)
 	}
+	sources := configure(fs, f.path)
+	if *printConfigFlag {
+		printConfig(fs, sources)
+		return
+	}
 
-	opts := describer.Options{
-		Tags:           *tags,
-		Candidates:     *candidates,
-		MatchPattern:   *match,
-		ExcludePattern: *exclude,
-		All:            *all,
-		ExactMatch:     *exactMatch,
-		FirstParent:    *firstParent,
+	commitish := fs.Arg(0)
+	if commitish != "" || *stdin || *contains {
+		if fs.Changed("dirty") || fs.Changed("broken") {
+			log.Fatal("--dirty and --broken are incompatible with commit-ishes and --contains")
+		}
+		// configured defaults only apply when describing the working tree
+		*dirty, *broken = "", ""
+	}
+	if *jsonOutput && *contains {
+		log.Fatal("--json is not supported with --contains")
+	}
+	var requirement semverdesc.Requirement
+	if *require != "" {
+		if *stdin || *contains || *recurseSubmodules {
+			log.Fatal("--require is not supported with --stdin, --contains or --recurse-submodules")
+		}
+		var err error
+		if requirement, err = semverdesc.ParseRequirement(*require); err != nil {
+			log.Fatal(err)
+		}
 	}
-	formatOpts := semverdesc.FormatOptions{
-		Abbrev:    *abbrev,
-		Long:      *long,
-		DirtyMark: *dirty,
+
+	opts := f.options()
+	opts.Broken = *broken != ""
+	opts.DirtySubmodules = *dirtySubmodules
+	opts.DirtyUntracked = *dirtyUntracked
+	opts.DirtyIgnore = *dirtyIgnore
+	opts.DirtyIgnoreSubmodules = *dirtyIgnoreSubmodules
+	opts.DirtyHash = *dirtyHash > 0
+	opts.Cache = !*noCache
+	// the working tree need not be checked unless something will show it,
+	// which lets cached results be used without running git at all
+	opts.SkipDirty = *dirty == "" && *broken == "" && *require == "" && !*jsonOutput &&
+		!*recurseSubmodules && !*dirtySubmodules && !*dirtyUntracked && len(*dirtyIgnore) == 0 &&
+		!*dirtyIgnoreSubmodules && *dirtyHash == 0
+	formatOpts := f.formatOptions()
+	formatOpts.DirtyMark = *dirty
+	formatOpts.BrokenMark = *broken
+	formatOpts.DirtyHashAbbrev = *dirtyHash
+
+	if *stdin {
+		if !describeStdin(&f, opts, formatOpts, *jsonOutput) {
+			os.Exit(1)
+		}
+		return
 	}
 
-	commitish := pflag.Arg(0)
-	d, err := describer.Describe(*path, commitish, opts)
+	if *recurseSubmodules {
+		if !describeSubmodules(&f, opts, formatOpts, *jsonOutput) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *contains {
+		c, err := describer.DescribeContains(f.path, commitish, opts)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Println(f.format(c, formatOpts))
+		return
+	}
+
+	d, err := describer.Describe(f.path, commitish, opts)
 	if err != nil {
-		// if was underlying git describe error, pass it along exactly
-		if exiterr, ok := err.(*exec.ExitError); ok {
-			fmt.Fprint(os.Stderr, string(exiterr.Stderr))
-			os.Exit(exiterr.ExitCode())
+		exitWithError(err)
+	}
+	if d.Approximate {
+		fmt.Fprintln(os.Stderr, "warning: repository is shallow, results may be approximate")
+	}
+	if requirement != 0 {
+		if err := d.Require(requirement); err != nil {
+			exitWithError(err)
+		}
+	}
+	if *jsonOutput {
+		fmt.Println(f.formatJSON(d, opts, formatOpts))
+		return
+	}
+	fmt.Println(f.format(d, formatOpts))
+}
+
+// describeStdin describes each commit-ish read from stdin, printing the
+// results one per line in the same order, as JSON if jsonOutput is set. A
+// commit-ish which can not be described results in an empty line (or null),
+// with the error reported on stderr. Returns whether all commit-ishes were
+// described successfully.
+func describeStdin(f *describeFlags, opts describer.Options, formatOpts semverdesc.FormatOptions, jsonOutput bool) bool {
+	var commitishes []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if c := strings.TrimSpace(scanner.Text()); c != "" {
+			commitishes = append(commitishes, c)
 		}
-		// otherwise, handle as an error
+	}
+	if err := scanner.Err(); err != nil {
 		log.Fatal(err)
-		os.Exit(1)
-	}
-
-	// format and print the results
-	//
-	// the prefix trimming option is handled locally rather than in the library
-	// since it is a convenience function for cross-platform CLI handiness, but
-	// is not necessary when using as a library since you can just handle with
-	// stdlib directly.
-	var formattedResults string
-	if *legacy {
-		formattedResults = d.FormatLegacy(formatOpts)
-	} else {
-		formattedResults = d.Format(formatOpts)
-	}
-	fmt.Println(strings.TrimPrefix(formattedResults, *trimPrefix))
+	}
+
+	ok := true
+	for _, r := range describer.DescribeMany(f.path, commitishes, opts) {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", r.Commitish, errorMessage(r.Err))
+			if jsonOutput {
+				fmt.Println("null")
+			} else {
+				fmt.Println()
+			}
+			ok = false
+			continue
+		}
+		if jsonOutput {
+			fmt.Println(f.formatJSON(r.Results, opts, formatOpts))
+		} else {
+			fmt.Println(f.format(r.Results, formatOpts))
+		}
+	}
+	return ok
+}
+
+// describeSubmodules describes the superproject and each of its submodules,
+// printing their paths and results separated by a tab, one per line, with the
+// superproject as ".". A submodule which can not be described results in a
+// "-", with the error reported on stderr. If jsonOutput is set, the results are
+// instead printed as a single JSON object, with the errors included. Returns
+// whether all submodules were described successfully.
+func describeSubmodules(f *describeFlags, opts describer.Options, formatOpts semverdesc.FormatOptions, jsonOutput bool) bool {
+	report, err := describer.DescribeSubmodules(f.path, opts)
+	if err != nil {
+		exitWithError(err)
+	}
+	if jsonOutput {
+		fmt.Println(f.formatSubmodulesJSON(report, opts, formatOpts))
+		ok := true
+		for _, s := range report.Submodules {
+			if s.Err != nil {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", s.Path, errorMessage(s.Err))
+				ok = false
+			}
+		}
+		return ok
+	}
+
+	fmt.Printf(".\t%v\n", f.format(report.Superproject, formatOpts))
+	ok := true
+	for _, s := range report.Submodules {
+		if s.Err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", s.Path, errorMessage(s.Err))
+			fmt.Printf("%v\t-\n", s.Path)
+			ok = false
+			continue
+		}
+		modified := ""
+		if s.Modified {
+			modified = "\t(modified)"
+		}
+		fmt.Printf("%v\t%v%v\n", s.Path, f.format(s.Results, formatOpts), modified)
+	}
+	return ok
+}
+
+// requireExitCodes are the exit codes for each reason results may fail to
+// meet --require, distinct from those of git itself and any other error.
+var requireExitCodes = map[semverdesc.Failure]int{
+	semverdesc.FailNotExact:      10,
+	semverdesc.FailDirty:         11,
+	semverdesc.FailBroken:        12,
+	semverdesc.FailNotSemver:     13,
+	semverdesc.FailPrerelease:    14,
+	semverdesc.FailNotPrerelease: 15,
+}
+
+// exitWithError reports err and exits with a non-zero status code.
+func exitWithError(err error) {
+	// if was underlying git describe error, pass it along exactly
+	if exiterr, ok := err.(*exec.ExitError); ok {
+		fmt.Fprint(os.Stderr, string(exiterr.Stderr))
+		os.Exit(exiterr.ExitCode())
+	}
+	if rerr, ok := err.(*semverdesc.RequirementError); ok {
+		log.Print(rerr)
+		os.Exit(requireExitCodes[rerr.Failure])
+	}
+	// otherwise, handle as an error
+	log.Fatal(err)
+	os.Exit(1)
+}
+
+// errorMessage returns a single line message for err, preferring the output
+// of an underlying git command if there was one.
+func errorMessage(err error) string {
+	if exiterr, ok := err.(*exec.ExitError); ok && len(exiterr.Stderr) > 0 {
+		return strings.TrimSpace(string(exiterr.Stderr))
+	}
+	return err.Error()
 }
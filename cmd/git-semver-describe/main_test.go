@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubcommand(t *testing.T) {
+	branches := map[string]bool{"next": true}
+	isBranch := func(name string) bool { return branches[name] }
+	tests := []struct {
+		name     string
+		args     []string
+		wantCmd  func([]string)
+		wantArgs []string
+	}{
+		{name: "no args"},
+		{name: "commit-ish", args: []string{"v1.0.0"}},
+		{name: "flags", args: []string{"--tags", "bump"}},
+		{name: "subcommand", args: []string{"bump", "patch"}, wantCmd: bumpCmd, wantArgs: []string{"patch"}},
+		{name: "revision named as subcommand", args: []string{"next", "--tags"}},
+		{name: "explicit subcommand", args: []string{"--subcommand=next", "--tags"}, wantCmd: nextCmd, wantArgs: []string{"--tags"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, ok := subcommand(tt.args, isBranch)
+			if ok != (tt.wantCmd != nil) {
+				t.Fatalf("subcommand() ok = %v, want %v", ok, tt.wantCmd != nil)
+			}
+			if !ok {
+				return
+			}
+			if reflect.ValueOf(cmd).Pointer() != reflect.ValueOf(tt.wantCmd).Pointer() {
+				t.Error("subcommand() returned the wrong command")
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("subcommand() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
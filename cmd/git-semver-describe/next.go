@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mroth/semverdesc/describer"
+)
+
+// nextCmd suggests the next version of a commit-ish from the Conventional
+// Commits since its tag, followed by the commits which decided it.
+func nextCmd(args []string) {
+	fs := newFlagSet("git semver-describe next",
+		"git semver-describe next [<options>] [<commit-ish>]",
+	)
+	var f describeFlags
+	f.addGitFlags(fs)
+	quiet := fs.Bool("quiet", false, "only print the next version")
+	f.addExtraFlags(fs)
+	fs.Parse(args)
+	configure(fs, f.path)
+
+	n, err := describer.Next(f.path, fs.Arg(0), f.options())
+	if err != nil {
+		exitWithError(err)
+	}
+	fmt.Println(strings.TrimPrefix(n.Next.String(), f.trimPrefix))
+	if *quiet {
+		return
+	}
+	fmt.Println()
+	printNext(n, f.abbrev)
+}
+
+// printNext prints the reasoning for a suggested next version, along with the
+// commits which drove it.
+func printNext(n *describer.NextVersion, abbrev uint) {
+	if n.Change == describer.ChangeNone {
+		fmt.Printf("none of the %d commit(s) since %v are a fix, feature or breaking change.\n",
+			len(n.Commits), n.Current)
+		return
+	}
+	if n.Change == describer.ChangeMajor && n.Current.Major == 0 {
+		fmt.Printf("%d commit(s) since %v make a %v change, bumping the minor version while the major version is zero:\n\n",
+			len(n.Drivers()), n.Current, n.Change)
+	} else {
+		fmt.Printf("%d commit(s) since %v make a %v change:\n\n",
+			len(n.Drivers()), n.Current, n.Change)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, c := range n.Drivers() {
+		hash := c.HashStr
+		if int(abbrev) > 0 && int(abbrev) < len(hash) {
+			hash = hash[:abbrev]
+		}
+		fmt.Fprintf(w, "  %v\t%v\n", hash, c.Subject)
+	}
+	w.Flush()
+}
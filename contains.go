@@ -0,0 +1,104 @@
+package semverdesc
+
+import (
+	"fmt"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// ContainsResults are the structured results from a `git describe --contains`
+// operation on a commit, ready to be formatted.
+//
+// Whereas DescribeResults locate a commit relative to the most recent tag that
+// precedes it, ContainsResults locate it relative to the first tag that
+// contains it, e.g. answering "which release shipped this commit?".
+type ContainsResults struct {
+	// The name of the first tag containing the commit
+	TagName string
+	// Number of commits the described commit precedes the tag by
+	Distance uint
+	// The SHA hash of the commit-ish used for the describe, converted to a
+	// string.
+	HashStr string
+}
+
+// ContainsPrerelease is the pre-release identifier applied to the containing
+// tag when formatting a commit which precedes it.
+const ContainsPrerelease = "pre"
+
+// String implements Stringer and is the equivalent of Format with
+// DefaultFormatOptions.
+func (cr *ContainsResults) String() string {
+	return cr.Format(DefaultFormatOptions())
+}
+
+// Format returns the semver contains string utilizing given FormatOptions.
+//
+// A commit that is not itself tagged comes *before* the tag that contains it,
+// so rather than build metadata alone, the tag is also marked as a pre-release
+// of itself, e.g. ten commits prior to v0.3.0 is formatted as
+// "v0.3.0-pre+10.gabc1234". If the containing tag is already a pre-release, the
+// identifier is appended to the existing pre-release instead, e.g.
+// "v0.3.0-rc1.pre+10.gabc1234". Note that SemVer precedence can not express
+// "before v0.3.0-rc1" precisely, so the latter will sort after the tag itself.
+//
+// An Abbrev of 0 omits the commit hash from the build metadata, but unlike
+// DescribeResults.Format it will not suppress the distance, as that would lose
+// the pre-release information.
+//
+// With Long, an exact match is formatted with build metadata alone, e.g.
+// "v0.3.0+0.gabc1234", as the commit is the tag itself rather than before it.
+//
+// DirtyMark has no meaning for a contains operation and is ignored.
+func (cr *ContainsResults) Format(opts FormatOptions) string {
+	if shouldUseShortContains(cr, opts) {
+		return cr.TagName
+	}
+	version := cr.TagName
+	if cr.Distance > 0 {
+		version += containsSeparator(cr.TagName) + ContainsPrerelease
+	}
+	version += fmt.Sprintf("+%v", cr.Distance)
+	if abbrev := effectiveAbbrev(cr.HashStr, opts); abbrev > 0 {
+		version += ".g" + cr.HashStr[:abbrev]
+	}
+	return version
+}
+
+// containsSeparator returns the separator preceding ContainsPrerelease for a
+// tag: "." if the version it names is already a pre-release, or "-" otherwise.
+// The version is the tag name itself, or else the longest suffix of it
+// following a "/" or "-" which is valid SemVer, e.g. the "v1.0.0" of
+// "my-app-v1.0.0" or "services/billing/v1.0.0".
+func containsSeparator(tag string) string {
+	for i := 0; i < len(tag); i++ {
+		if i > 0 && tag[i-1] != '/' && tag[i-1] != '-' {
+			continue
+		}
+		if v, err := semver.Parse(tag[i:]); err == nil {
+			if len(v.Prerelease) > 0 {
+				return "."
+			}
+			return "-"
+		}
+	}
+	return "-"
+}
+
+// FormatLegacy returns the contains string in a format similar to what `git
+// describe --contains` would, e.g. "v0.3.0~10". This is provided for
+// comparative reasons.
+//
+// Note that git will walk merge parents in its output (e.g. "v0.3.0~2^2~3"),
+// whereas the legacy format here always expresses the total commit count.
+func (cr *ContainsResults) FormatLegacy(opts FormatOptions) string {
+	if shouldUseShortContains(cr, opts) {
+		return cr.TagName
+	}
+	return fmt.Sprintf("%v~%v", cr.TagName, cr.Distance)
+}
+
+// determine whether short format is appropriate for contains results
+func shouldUseShortContains(cr *ContainsResults, opts FormatOptions) bool {
+	return cr.Distance == 0 && !opts.Long
+}
@@ -0,0 +1,148 @@
+package semverdesc
+
+import "testing"
+
+var containsTestCases = []struct {
+	name   string
+	cont   ContainsResults
+	opts   FormatOptions
+	want   string
+	legacy string
+}{
+	{
+		name: "default",
+		cont: ContainsResults{
+			TagName:  "v0.3.0",
+			Distance: 10,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "v0.3.0-pre+10.gd71dd50",
+		legacy: "v0.3.0~10",
+	},
+	{
+		name: "exact match",
+		cont: ContainsResults{
+			TagName:  "v0.3.0",
+			Distance: 0,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "v0.3.0",
+		legacy: "v0.3.0",
+	},
+	{
+		name: "exact match with long",
+		cont: ContainsResults{
+			TagName:  "v0.3.0",
+			Distance: 0,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts: FormatOptions{
+			Long: true,
+		},
+		want:   "v0.3.0+0.gd71dd50",
+		legacy: "v0.3.0~0",
+	},
+	{
+		name: "exact match with long non-semver tag",
+		cont: ContainsResults{
+			TagName:  "nightly",
+			Distance: 0,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts: FormatOptions{
+			Long: true,
+		},
+		want:   "nightly+0.gd71dd50",
+		legacy: "nightly~0",
+	},
+	{
+		name: "exact match with long prerelease tag",
+		cont: ContainsResults{
+			TagName:  "v1.0.0-rc2",
+			Distance: 0,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts: FormatOptions{
+			Long: true,
+		},
+		want:   "v1.0.0-rc2+0.gd71dd50",
+		legacy: "v1.0.0-rc2~0",
+	},
+	{
+		name: "prerelease tag",
+		cont: ContainsResults{
+			TagName:  "v1.0.0-rc2",
+			Distance: 3,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "v1.0.0-rc2.pre+3.gd71dd50",
+		legacy: "v1.0.0-rc2~3",
+	},
+	{
+		name: "hyphenated tag",
+		cont: ContainsResults{
+			TagName:  "my-app-v1.0.0",
+			Distance: 3,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "my-app-v1.0.0-pre+3.gd71dd50",
+		legacy: "my-app-v1.0.0~3",
+	},
+	{
+		name: "hyphenated prerelease tag",
+		cont: ContainsResults{
+			TagName:  "my-app-v1.0.0-rc.2",
+			Distance: 3,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "my-app-v1.0.0-rc.2.pre+3.gd71dd50",
+		legacy: "my-app-v1.0.0-rc.2~3",
+	},
+	{
+		name: "hyphenated non-semver tag",
+		cont: ContainsResults{
+			TagName:  "nightly-build",
+			Distance: 3,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   DefaultFormatOptions(),
+		want:   "nightly-build-pre+3.gd71dd50",
+		legacy: "nightly-build~3",
+	},
+	{
+		name: "zero abbrev",
+		cont: ContainsResults{
+			TagName:  "v0.3.0",
+			Distance: 10,
+			HashStr:  "d71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		opts:   FormatOptions{},
+		want:   "v0.3.0-pre+10",
+		legacy: "v0.3.0~10",
+	},
+}
+
+func TestContainsResults_Format(t *testing.T) {
+	for _, tc := range containsTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cont.Format(tc.opts); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContainsResults_FormatLegacy(t *testing.T) {
+	for _, tc := range containsTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cont.FormatLegacy(tc.opts); got != tc.legacy {
+				t.Errorf("got %v, want %v", got, tc.legacy)
+			}
+		})
+	}
+}
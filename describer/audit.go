@@ -0,0 +1,310 @@
+package describer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// AuditTag is a tag examined by Audit.
+type AuditTag struct {
+	// The name of the tag, without any TagPrefix
+	Name string
+	// Whether the tag is annotated, rather than lightweight
+	Annotated bool
+	// The full SHA hash of the commit tagged, as a string, empty if the tag
+	// does not point at a commit
+	HashStr string
+	// The version of the tag, nil if it is not valid SemVer
+	Version *semver.Version
+}
+
+// Problem is a kind of problem with the tags of a repository found by Audit.
+type Problem int
+
+// Problems which may be found by Audit.
+const (
+	// ProblemNotSemver is a tag which is not valid SemVer.
+	ProblemNotSemver Problem = iota + 1
+	// ProblemDuplicate is several tags for the same version, differing only
+	// by their prefix or build metadata, e.g. v1.2.3 and 1.2.3.
+	ProblemDuplicate
+	// ProblemMixedTypes is a mix of annotated and lightweight tags, which git
+	// describe treats differently unless Tags is set.
+	ProblemMixedTypes
+	// ProblemOutOfOrder is a tag with lower precedence than a tag on one of
+	// the ancestors of its commit, i.e. a release out of order with history.
+	ProblemOutOfOrder
+)
+
+var problemNames = map[Problem]string{
+	ProblemNotSemver:  "not-semver",
+	ProblemDuplicate:  "duplicate",
+	ProblemMixedTypes: "mixed-types",
+	ProblemOutOfOrder: "out-of-order",
+}
+
+// String returns the name of the problem.
+func (p Problem) String() string {
+	if name, ok := problemNames[p]; ok {
+		return name
+	}
+	return "Problem(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the problem by name.
+func (p Problem) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Finding is an occurrence of a Problem.
+type Finding struct {
+	Problem Problem
+	// The names of the tags involved, without any TagPrefix. For
+	// ProblemOutOfOrder, the out of order tag followed by the ancestor tag it
+	// has lower precedence than, and for ProblemMixedTypes the tags of the
+	// less common type.
+	Tags []string
+	// A description of the problem
+	Message string
+}
+
+// AuditReport is the result of Audit.
+type AuditReport struct {
+	// The tags examined, ordered by name
+	Tags []AuditTag
+	// The problems found, ordered by Problem and then tag name
+	Findings []Finding
+}
+
+// Audit examines the tags of the git repository located at path for problems
+// which make them unreliable for describing versions: tags which are not
+// SemVer, duplicate versions, a mix of annotated and lightweight tags, and
+// releases whose precedence is out of order with history.
+//
+// Only tags under the TagPrefix of opts which match its MatchPattern and
+// ExcludePattern are examined, regardless of SemverOnly. As with Describe, the
+// returned error may be of type exec.ExitError.
+func Audit(path string, opts Options) (*AuditReport, error) {
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	// for-each-ref patterns only match whole path components, so a prefix
+	// such as "lib-" is filtered here instead
+	output, err := gitCmd(path, "for-each-ref", "--format="+auditRefFormat, "refs/tags/").Output()
+	if err != nil {
+		return nil, err
+	}
+	all, err := parseAuditRefs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := opts
+	filter.SemverOnly = false
+	report := &AuditReport{}
+	for _, t := range all {
+		if matchesPatterns(t.Name, filter) {
+			report.Tags = append(report.Tags, auditVersion(t, opts.TagPrefix))
+		}
+	}
+
+	report.Findings = append(report.Findings, auditSemver(report.Tags)...)
+	report.Findings = append(report.Findings, auditDuplicates(report.Tags)...)
+	report.Findings = append(report.Findings, auditTypes(report.Tags)...)
+	ancestors := func(hash string) ([]string, error) {
+		return mergedTags(path, hash, opts.TagPrefix)
+	}
+	outOfOrder, err := auditOrder(report.Tags, ancestors)
+	if err != nil {
+		return nil, err
+	}
+	report.Findings = append(report.Findings, outOfOrder...)
+	return report, nil
+}
+
+// auditRefFormat is the git for-each-ref format parsed by parseAuditRefs: the
+// ref name, the type and name of the object it points at, and the type and
+// name of the object an annotated tag points at.
+const auditRefFormat = "%(refname)%00%(objecttype)%00%(objectname)%00%(*objecttype)%00%(*objectname)"
+
+// parseAuditRefs parses the output of git for-each-ref with auditRefFormat,
+// leaving the Version of each tag unset.
+func parseAuditRefs(output []byte) ([]AuditTag, error) {
+	var tags []AuditTag
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 5 {
+			return nil, errors.New("unable to match: [" + line + "]")
+		}
+		t := AuditTag{
+			Name:      strings.TrimPrefix(fields[0], "refs/tags/"),
+			Annotated: fields[1] == "tag",
+		}
+		switch {
+		case fields[1] == "commit":
+			t.HashStr = fields[2]
+		case t.Annotated && fields[3] == "commit":
+			t.HashStr = fields[4]
+		}
+		tags = append(tags, t)
+	}
+	return tags, scanner.Err()
+}
+
+// auditVersion returns t with prefix stripped from its name, and its Version
+// parsed from what remains.
+func auditVersion(t AuditTag, prefix string) AuditTag {
+	t.Name = strings.TrimPrefix(t.Name, prefix)
+	if v, err := semver.Parse(t.Name); err == nil {
+		t.Version = &v
+	}
+	return t
+}
+
+// mergedTags returns the names of the tags under prefix whose commit is
+// reachable from the commit hash in the repository located at repo, without
+// the prefix.
+func mergedTags(repo, hash, prefix string) ([]string, error) {
+	output, err := gitCmd(repo, "for-each-ref", "--format=%(refname)",
+		"--merged="+hash, "refs/tags/").Output()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, ref := range parseRevList(output) {
+		if name := strings.TrimPrefix(ref, "refs/tags/"); strings.HasPrefix(name, prefix) {
+			names = append(names, strings.TrimPrefix(name, prefix))
+		}
+	}
+	return names, nil
+}
+
+// auditSemver finds the tags which are not SemVer.
+func auditSemver(tags []AuditTag) []Finding {
+	var findings []Finding
+	for _, t := range tags {
+		if t.Version == nil {
+			findings = append(findings, Finding{
+				Problem: ProblemNotSemver,
+				Tags:    []string{t.Name},
+				Message: t.Name + " is not valid SemVer",
+			})
+		}
+	}
+	return findings
+}
+
+// auditDuplicates finds the groups of tags with the same version.
+func auditDuplicates(tags []AuditTag) []Finding {
+	var findings []Finding
+	seen := make([]bool, len(tags))
+	for i, t := range tags {
+		if t.Version == nil || seen[i] {
+			continue
+		}
+		group := []string{t.Name}
+		for j := i + 1; j < len(tags); j++ {
+			u := tags[j]
+			if u.Version != nil && semver.Compare(*t.Version, *u.Version) == 0 {
+				group = append(group, u.Name)
+				seen[j] = true
+			}
+		}
+		if len(group) > 1 {
+			findings = append(findings, Finding{
+				Problem: ProblemDuplicate,
+				Tags:    group,
+				Message: strings.Join(group, ", ") + " are the same version",
+			})
+		}
+	}
+	return findings
+}
+
+// auditTypes finds the less common type of tag, if both annotated and
+// lightweight tags are used.
+func auditTypes(tags []AuditTag) []Finding {
+	var annotated, lightweight []string
+	for _, t := range tags {
+		if t.Annotated {
+			annotated = append(annotated, t.Name)
+		} else {
+			lightweight = append(lightweight, t.Name)
+		}
+	}
+	if len(annotated) == 0 || len(lightweight) == 0 {
+		return nil
+	}
+	if len(annotated) < len(lightweight) {
+		return []Finding{{
+			Problem: ProblemMixedTypes,
+			Tags:    annotated,
+			Message: fmt.Sprintf("%d annotated tag(s) among %d lightweight tag(s): %v",
+				len(annotated), len(lightweight), strings.Join(annotated, ", ")),
+		}}
+	}
+	return []Finding{{
+		Problem: ProblemMixedTypes,
+		Tags:    lightweight,
+		Message: fmt.Sprintf("%d lightweight tag(s) among %d annotated tag(s), which git describe ignores without --tags: %v",
+			len(lightweight), len(annotated), strings.Join(lightweight, ", ")),
+	}}
+}
+
+// auditOrder finds the SemVer tags with lower precedence than the highest
+// SemVer tag on an ancestor of their commit, using ancestors to list the
+// names of the tags reachable from a commit.
+func auditOrder(tags []AuditTag, ancestors func(hash string) ([]string, error)) ([]Finding, error) {
+	byName := make(map[string]AuditTag, len(tags))
+	for _, t := range tags {
+		byName[t.Name] = t
+	}
+
+	var findings []Finding
+	reachable := make(map[string][]string)
+	for _, t := range tags {
+		if t.Version == nil || t.HashStr == "" {
+			continue
+		}
+		names, ok := reachable[t.HashStr]
+		if !ok {
+			var err error
+			if names, err = ancestors(t.HashStr); err != nil {
+				return nil, err
+			}
+			reachable[t.HashStr] = names
+		}
+
+		var highest *AuditTag
+		for _, name := range names {
+			a, ok := byName[name]
+			if !ok || a.Version == nil || a.HashStr == t.HashStr {
+				continue
+			}
+			if highest == nil || semver.Compare(*a.Version, *highest.Version) > 0 {
+				highest = &a
+			}
+		}
+		if highest != nil && semver.Compare(*t.Version, *highest.Version) < 0 {
+			findings = append(findings, Finding{
+				Problem: ProblemOutOfOrder,
+				Tags:    []string{t.Name, highest.Name},
+				Message: fmt.Sprintf("%v has lower precedence than %v, which tags an ancestor of its commit",
+					t.Name, highest.Name),
+			})
+		}
+	}
+	return findings, nil
+}
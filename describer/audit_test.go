@@ -0,0 +1,253 @@
+package describer
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// auditTag returns an AuditTag for tests, parsing its version from the name.
+func auditTag(name string, annotated bool, hash string) AuditTag {
+	t := AuditTag{Name: name, Annotated: annotated, HashStr: hash}
+	if v, err := semver.Parse(name); err == nil {
+		t.Version = &v
+	}
+	return t
+}
+
+func Test_parseAuditRefs(t *testing.T) {
+	output := []byte("refs/tags/latest\x00commit\x0056dc2041f2c45ab15d41e63058c1c44fff905e81\x00\x00\n" +
+		"refs/tags/v1.0.0\x00tag\x00b5d40460d8e9b1e5a1d6c4b1c6bcb1b0e0b1f1a2\x00commit\x0071dd5072d51458a534ca7e0ec7c181d84754774d\n" +
+		"refs/tags/v1.0.0-tree\x00tree\x004b825dc642cb6eb9a060e54bf8d69288fbee4904\x00\x00\n")
+	want := []AuditTag{
+		{Name: "latest", HashStr: "56dc2041f2c45ab15d41e63058c1c44fff905e81"},
+		{Name: "v1.0.0", Annotated: true, HashStr: "71dd5072d51458a534ca7e0ec7c181d84754774d"},
+		{Name: "v1.0.0-tree"},
+	}
+	got, err := parseAuditRefs(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAuditRefs() = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseAuditRefs([]byte("refs/tags/v1.0.0\x00commit\n")); err == nil {
+		t.Error("parseAuditRefs() expected error for unexpected format")
+	}
+}
+
+func Test_auditVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		prefix string
+		want   AuditTag
+	}{
+		{name: "no prefix", tag: "v1.2.3", want: auditTag("v1.2.3", true, "a")},
+		{name: "prefix", tag: "services/billing/v1.2.3", prefix: "services/billing/", want: auditTag("v1.2.3", true, "a")},
+		{name: "not semver", tag: "services/billing/latest", prefix: "services/billing/", want: auditTag("latest", true, "a")},
+		{name: "prefixed without prefix", tag: "services/billing/v1.2.3", want: auditTag("services/billing/v1.2.3", true, "a")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := auditVersion(AuditTag{Name: tt.tag, Annotated: true, HashStr: "a"}, tt.prefix)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudit_component(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "services/billing/v1.0.0")
+	git(t, repo, "tag", "--annotate", "--message", "r", "v3.0.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "next")
+	git(t, repo, "tag", "--annotate", "--message", "r", "services/billing/v0.9.0")
+	git(t, repo, "tag", "--annotate", "--message", "r", "services/billing/v1.1.0")
+
+	report, err := Audit(repo, Options{TagPrefix: "services/billing/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tag := range report.Tags {
+		if tag.Version == nil {
+			t.Errorf("Audit() tag %v has no Version", tag.Name)
+		}
+		names = append(names, tag.Name)
+	}
+	if want := []string{"v0.9.0", "v1.0.0", "v1.1.0"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Audit() tags = %v, want %v", names, want)
+	}
+	want := []Finding{{
+		Problem: ProblemOutOfOrder,
+		Tags:    []string{"v0.9.0", "v1.0.0"},
+		Message: "v0.9.0 has lower precedence than v1.0.0, which tags an ancestor of its commit",
+	}}
+	if !reflect.DeepEqual(report.Findings, want) {
+		t.Errorf("Audit() findings = %+v, want %+v", report.Findings, want)
+	}
+}
+
+func TestAudit_partialPrefix(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "lib-v1.1.0")
+	git(t, repo, "tag", "--annotate", "--message", "r", "app-v2.0.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "next")
+	git(t, repo, "tag", "--annotate", "--message", "r", "lib-v1.0.0")
+
+	report, err := Audit(repo, Options{TagPrefix: "lib-"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tag := range report.Tags {
+		names = append(names, tag.Name)
+	}
+	if want := []string{"v1.0.0", "v1.1.0"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Audit() tags = %v, want %v", names, want)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Problem != ProblemOutOfOrder {
+		t.Errorf("Audit() findings = %+v, want v1.0.0 out of order", report.Findings)
+	}
+}
+
+func Test_auditSemver(t *testing.T) {
+	tags := []AuditTag{
+		auditTag("latest", false, "a"),
+		auditTag("v1.0.0", true, "b"),
+		auditTag("v1.1", true, "c"),
+	}
+	var got []string
+	for _, f := range auditSemver(tags) {
+		if f.Problem != ProblemNotSemver {
+			t.Errorf("auditSemver() problem = %v, want %v", f.Problem, ProblemNotSemver)
+		}
+		got = append(got, f.Tags...)
+	}
+	if want := []string{"latest", "v1.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("auditSemver() tags = %v, want %v", got, want)
+	}
+}
+
+func Test_auditDuplicates(t *testing.T) {
+	tags := []AuditTag{
+		auditTag("1.2.3", true, "a"),
+		auditTag("v1.0.0", true, "b"),
+		auditTag("v1.2.3", true, "a"),
+		auditTag("v1.2.3+build.1", true, "a"),
+		auditTag("v1.2.3-rc.1", true, "c"),
+		auditTag("v1.3.0-rc.1", true, "d"),
+		auditTag("v1.3.0-rc.1+meta", true, "d"),
+	}
+	want := [][]string{
+		{"1.2.3", "v1.2.3", "v1.2.3+build.1"},
+		{"v1.3.0-rc.1", "v1.3.0-rc.1+meta"},
+	}
+	var got [][]string
+	for _, f := range auditDuplicates(tags) {
+		got = append(got, f.Tags)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("auditDuplicates() = %v, want %v", got, want)
+	}
+}
+
+func Test_auditTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []AuditTag
+		want []string
+	}{
+		{
+			name: "all annotated",
+			tags: []AuditTag{auditTag("v1.0.0", true, "a"), auditTag("v1.1.0", true, "b")},
+			want: nil,
+		},
+		{
+			name: "all lightweight",
+			tags: []AuditTag{auditTag("v1.0.0", false, "a"), auditTag("v1.1.0", false, "b")},
+			want: nil,
+		},
+		{
+			name: "mostly annotated",
+			tags: []AuditTag{auditTag("v1.0.0", true, "a"), auditTag("v1.1.0", false, "b"), auditTag("v1.2.0", true, "c")},
+			want: []string{"v1.1.0"},
+		},
+		{
+			name: "mostly lightweight",
+			tags: []AuditTag{auditTag("v1.0.0", true, "a"), auditTag("v1.1.0", false, "b"), auditTag("v1.2.0", false, "c")},
+			want: []string{"v1.0.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range auditTypes(tt.tags) {
+				got = append(got, f.Tags...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_auditOrder(t *testing.T) {
+	// history of a -> b -> c -> d, with e branching from b
+	history := map[string][]string{
+		"a": {"a"},
+		"b": {"a", "b"},
+		"c": {"a", "b", "c"},
+		"d": {"a", "b", "c", "d"},
+		"e": {"a", "b", "e"},
+	}
+	tags := []AuditTag{
+		auditTag("latest", false, "d"),
+		auditTag("v1.0.0", true, "a"),
+		auditTag("v1.1.0", true, "c"),
+		auditTag("v1.1.0-rc.1", true, "c"),
+		auditTag("v1.0.1", true, "e"),
+		auditTag("v2.0.0", true, "b"),
+		auditTag("v1.2.0", true, "d"),
+	}
+	// tags are listed by name, and reachable from each commit
+	ancestors := func(hash string) ([]string, error) {
+		var names []string
+		for _, t := range tags {
+			for _, h := range history[hash] {
+				if t.HashStr == h {
+					names = append(names, t.Name)
+				}
+			}
+		}
+		return names, nil
+	}
+
+	got, err := auditOrder(tags, ancestors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"v1.1.0", "v2.0.0"},
+		{"v1.1.0-rc.1", "v2.0.0"},
+		{"v1.0.1", "v2.0.0"},
+		{"v1.2.0", "v2.0.0"},
+	}
+	var gotTags [][]string
+	for _, f := range got {
+		if f.Problem != ProblemOutOfOrder {
+			t.Errorf("auditOrder() problem = %v, want %v", f.Problem, ProblemOutOfOrder)
+		}
+		gotTags = append(gotTags, f.Tags)
+	}
+	if !reflect.DeepEqual(gotTags, want) {
+		t.Errorf("auditOrder() = %v, want %v", gotTags, want)
+	}
+}
@@ -0,0 +1,296 @@
+package describer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+)
+
+// cacheFileName is the name of the cache file, within the git directory.
+const cacheFileName = "semverdesc-cache"
+
+// describeCache is an on-disk cache of describe results for a repository.
+//
+// All entries are valid for a single state of the repository, which covers
+// HEAD and every ref (including tags), so the cache is discarded as a whole
+// whenever any of them change.
+//
+// The state of the working tree is deliberately not cached, as it cannot be
+// validated without git effectively checking it anyway, so the entries hold
+// only the results for the commit being described.
+type describeCache struct {
+	file    string
+	State   string                `json:"state"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+// cacheEntry are the cached results of describing a commit.
+type cacheEntry struct {
+	TagName      string   `json:"tag"`
+	Distance     uint     `json:"distance"`
+	HashStr      string   `json:"hash"`
+	Alternatives []string `json:"alternatives,omitempty"`
+	Approximate  bool     `json:"approximate,omitempty"`
+}
+
+// loadCache loads the cache for the repository located at path, which is
+// empty if there is none yet or it is for a previous state of the repository.
+func loadCache(path string) (*describeCache, error) {
+	gitDir, commonDir, err := findGitDirs(path)
+	if err != nil {
+		return nil, err
+	}
+	state, err := repoState(gitDir, commonDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &describeCache{file: filepath.Join(gitDir, cacheFileName)}
+	data, err := ioutil.ReadFile(c.file)
+	if err == nil {
+		// a corrupt cache is simply replaced
+		json.Unmarshal(data, c)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if c.State != state || c.Entries == nil {
+		c.State, c.Entries = state, make(map[string]cacheEntry)
+	}
+	return c, nil
+}
+
+// get returns the cached results for key, if any. A nil cache never has any.
+func (c *describeCache) get(key string) (*semverdesc.DescribeResults, bool) {
+	if c == nil {
+		return nil, false
+	}
+	e, ok := c.Entries[key]
+	if !ok {
+		return nil, false
+	}
+	return &semverdesc.DescribeResults{
+		TagName:      e.TagName,
+		Distance:     e.Distance,
+		HashStr:      e.HashStr,
+		Alternatives: e.Alternatives,
+		Approximate:  e.Approximate,
+	}, true
+}
+
+// put adds the results for key to the cache and saves it. A nil cache is left
+// as is.
+//
+// The cache file is replaced atomically, so concurrent describes never see a
+// partially written cache, although they may lose each others entries.
+func (c *describeCache) put(key string, d *semverdesc.DescribeResults) error {
+	if c == nil {
+		return nil
+	}
+	c.Entries[key] = cacheEntry{
+		TagName:      d.TagName,
+		Distance:     d.Distance,
+		HashStr:      d.HashStr,
+		Alternatives: d.Alternatives,
+		Approximate:  d.Approximate,
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(c.file), cacheFileName)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file)
+}
+
+// cacheKey returns the key for describing commitish in the repository located
+// at path with opts, which covers everything other than the repository state
+// that the results depend on.
+func cacheKey(path, commitish string, opts Options) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	encodedOpts, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", abs, commitish, encodedOpts)
+	if opts.GoModule {
+		gomod, err := ioutil.ReadFile(filepath.Join(path, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		h.Write(gomod)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// repoState returns a fingerprint of HEAD and all refs of a repository, which
+// changes whenever any of them are updated.
+//
+// Rather than asking git, which would cost as much as the describe the cache
+// avoids, this reads the values of the refs straight from the packed-refs and
+// loose ref files, so it is unavailable for repositories storing refs in a
+// reftable. The shallow file is included, as deepening a shallow clone changes
+// the results without updating any refs, along with the modification time of
+// the index, which any checkout, commit or reset rewrites, as a safeguard
+// against ref updates missed by the rest.
+func repoState(gitDir, commonDir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(commonDir, "reftable")); err == nil {
+		return "", errors.New("unable to read refs from reftable")
+	}
+	refs, err := readRefs(commonDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := ioutil.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	target := strings.TrimSpace(string(head))
+	fmt.Fprintf(h, "HEAD\x00%s\x00%s\x00", target, refs[strings.TrimPrefix(target, "ref: ")])
+
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, refs[name])
+	}
+
+	shallow, err := ioutil.ReadFile(filepath.Join(commonDir, "shallow"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	fmt.Fprintf(h, "%s\x00", shallow)
+	if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		fmt.Fprintf(h, "%d\x00", info.ModTime().UnixNano())
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readRefs returns the value of each ref of a repository using the files ref
+// storage, keyed by name, with loose refs taking priority over packed-refs as
+// they do for git.
+func readRefs(commonDir string) (map[string]string, error) {
+	refs := make(map[string]string)
+	packed, err := ioutil.ReadFile(filepath.Join(commonDir, "packed-refs"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(packed), "\n") {
+		// skip the header and the peeled values of annotated tags
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, errors.New("unable to parse packed-refs: [" + line + "]")
+		}
+		refs[fields[1]] = fields[0]
+	}
+
+	err = filepath.Walk(filepath.Join(commonDir, "refs"), func(f string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		value, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(commonDir, f)
+		if err != nil {
+			return err
+		}
+		refs[filepath.ToSlash(name)] = strings.TrimSpace(string(value))
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// findGitDirs locates the git directory of the working tree containing path,
+// along with its common directory, which differ for linked worktrees.
+func findGitDirs(path string) (gitDir, commonDir string, err error) {
+	if os.Getenv("GIT_DIR") != "" {
+		return "", "", errors.New("unable to locate git directory from GIT_DIR")
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return dotGit, dotGit, nil
+			}
+			return readGitFile(dotGit)
+		} else if !os.IsNotExist(err) {
+			return "", "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", errors.New("not a git repository: " + path)
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves a .git file, as used by linked worktrees and
+// submodules, which points at the actual git directory.
+func readGitFile(file string) (gitDir, commonDir string, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", "", errors.New("invalid gitfile format: " + file)
+	}
+	gitDir = strings.TrimPrefix(line, "gitdir: ")
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(file), gitDir)
+	}
+
+	commonDir = gitDir
+	data, err = ioutil.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err == nil {
+		commonDir = string(bytes.TrimSpace(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", "", err
+	}
+	return gitDir, commonDir, nil
+}
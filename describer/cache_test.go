@@ -0,0 +1,146 @@
+package describer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/semverdesc"
+)
+
+// fakeRepo creates a minimal git directory layout (without any objects) in a
+// temporary directory, returning the path of its working tree, which the caller
+// should remove when done.
+func fakeRepo(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "semverdesc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(dir, ".git", "refs", "heads", "main"),
+		"56dc2041f2c45ab15d41e63058c1c44fff905e81\n")
+	return dir
+}
+
+func writeFile(t *testing.T, name, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_describeCache(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "second")
+	git(t, repo, "tag", "v1.2.3", "HEAD~1")
+	hash := git(t, repo, "rev-parse", "HEAD")
+	d := &semverdesc.DescribeResults{
+		TagName:  "v1.2.3",
+		Distance: 1,
+		HashStr:  hash,
+		Dirty:    true,
+	}
+	want := &semverdesc.DescribeResults{
+		TagName:  "v1.2.3",
+		Distance: 1,
+		HashStr:  hash,
+	}
+
+	c, err := loadCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.get("key"); ok {
+		t.Fatal("get() on empty cache found an entry")
+	}
+	if err := c.put("key", d); err != nil {
+		t.Fatal(err)
+	}
+
+	// packing refs changes how they are stored, but not what they resolve to
+	git(t, repo, "pack-refs", "--all")
+	c, err = loadCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.get("key"); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("get() = %v, %v, want %v, true", got, ok, want)
+	}
+
+	// moving a tag rewrites a ref of the same size, possibly within the same
+	// modification time
+	git(t, repo, "tag", "--force", "v1.2.3", "HEAD")
+	c, err = loadCache(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.get("key"); ok {
+		t.Error("get() found an entry after refs changed")
+	}
+}
+
+func TestDescribe_cached(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "v1.0.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "second")
+	opts := Options{Tags: true, Candidates: DefaultCandidatesOption, Cache: true, SkipDirty: true}
+	want, err := Describe(repo, "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without git, the results can only come from the cache
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", "")
+	got, err := Describe(repo, "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Describe() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_findGitDirs(t *testing.T) {
+	repo := fakeRepo(t)
+	defer os.RemoveAll(repo)
+	gitDir := filepath.Join(repo, ".git")
+	worktree := filepath.Join(repo, "wt")
+	worktreeGitDir := filepath.Join(gitDir, "worktrees", "wt")
+	writeFile(t, filepath.Join(worktree, ".git"), "gitdir: "+worktreeGitDir+"\n")
+	writeFile(t, filepath.Join(worktreeGitDir, "commondir"), "../..\n")
+	if err := os.MkdirAll(filepath.Join(repo, "sub", "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		wantGitDir    string
+		wantCommonDir string
+	}{
+		{"top level", repo, gitDir, gitDir},
+		{"subdirectory", filepath.Join(repo, "sub", "dir"), gitDir, gitDir},
+		{"linked worktree", worktree, worktreeGitDir, gitDir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGitDir, gotCommonDir, err := findGitDirs(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotGitDir != tt.wantGitDir || gotCommonDir != tt.wantCommonDir {
+				t.Errorf("findGitDirs() = %v, %v, want %v, %v",
+					gotGitDir, gotCommonDir, tt.wantGitDir, tt.wantCommonDir)
+			}
+		})
+	}
+}
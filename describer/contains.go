@@ -0,0 +1,156 @@
+package describer
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/localgit"
+)
+
+// DescribeContains attempts to find the first tag which contains the
+// commit-ish in a git repository located at path, e.g. answering "which
+// release shipped this commit?". For the default case (HEAD), set the
+// commitish as the zero value.
+//
+// This is the equivalent of `git describe --contains`, which automatically
+// implies Tags. Of the Options, only All, MatchPattern, ExcludePattern,
+// SemverOnly, TagPrefix and GoModule are meaningful for a contains operation,
+// the rest are ignored.
+//
+// As with Describe, the returned error may be of type exec.ExitError if there
+// was an error condition returned from an underlying git command.
+func DescribeContains(path, commitish string, opts Options) (*semverdesc.ContainsResults, error) {
+	if commitish == "" {
+		commitish = "HEAD"
+	}
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := describeContains(path, commitish, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := revParse(path, commitish+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+	distance, err := revCount(path, hash+".."+tag)
+	if err != nil {
+		return nil, err
+	}
+
+	tagName := strings.TrimPrefix(tag, opts.TagPrefix)
+	if err := checkGoModule(tagName, opts); err != nil {
+		return nil, err
+	}
+
+	return &semverdesc.ContainsResults{
+		TagName:  tagName,
+		Distance: distance,
+		HashStr:  hash,
+	}, nil
+}
+
+// describeContains returns the name of the first tag containing commitish,
+// searching again without any tag git finds which is not acceptable.
+func describeContains(path, commitish string, opts Options) (string, error) {
+	for i := 0; i < maxSearches; i++ {
+		cmd, err := buildContainsCmd(path, commitish, opts)
+		if err != nil {
+			return "", err
+		}
+		output, err := cmd.Output()
+		if err != nil {
+			return "", err
+		}
+		tag, err := parseContains(output)
+		if err != nil || acceptsTag(tag, opts) {
+			return tag, err
+		}
+		// git chose a tag which only looks like a version, so search again
+		// without it, or any other such tag containing the commit
+		target := commitish
+		if target == "" {
+			target = "HEAD"
+		}
+		if opts, err = rejectAll(path, "--contains="+target, opts.reject(tag)); err != nil {
+			return "", err
+		}
+	}
+	return "", errTooManySearches
+}
+
+// buildContainsCmd creates the localgit shell command to find the tag
+// containing commitish.
+func buildContainsCmd(path, commitish string, opts Options) (*exec.Cmd, error) {
+	match, exclude := searchPatterns(opts)
+	all, _ := searchRefs(opts)
+
+	gdOpts := localgit.NewDescribeOptions().Set(func(o *localgit.DescribeOptions) {
+		o.Contains = true
+		o.All = all
+		o.MatchPatterns = match
+		o.ExcludePatterns = exclude
+	})
+	if err := gdOpts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := gdOpts.Check(); err != nil {
+		return nil, err
+	}
+
+	args := []string{"describe"}
+	args = append(args, gdOpts.Flags()...)
+	args = append(args, commitish)
+	return gitCmd(path, args...), nil
+}
+
+// parseContains extracts the tag name from `git describe --contains` output,
+// which is in the form of a revision relative to the tag, e.g. "v0.3.0~10",
+// "v0.3.0~2^2~3", or "v0.3.0^0" for an exact match.
+func parseContains(output []byte) (string, error) {
+	output = bytes.TrimSuffix(output, []byte("\n"))
+	if len(output) == 0 {
+		return "", errors.New("received empty output")
+	}
+	// neither `~` nor `^` are valid in a refname, so the first instance of
+	// either marks the end of the tag.
+	tag := output
+	if i := bytes.IndexAny(output, "~^"); i != -1 {
+		tag = output[:i]
+	}
+	if len(tag) == 0 {
+		return "", errors.New("unable to match: [" + string(output) + "]")
+	}
+	return string(tag), nil
+}
+
+// revParse returns the full object name for rev.
+func revParse(path, rev string) (string, error) {
+	output, err := gitCmd(path, "rev-parse", "--verify", rev).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(output)), nil
+}
+
+// revCount returns the number of commits in the revision range.
+func revCount(path, revRange string) (uint, error) {
+	output, err := gitCmd(path, "rev-list", "--count", revRange).Output()
+	if err != nil {
+		return 0, err
+	}
+	digits := string(bytes.TrimSpace(output))
+	count, err := strconv.ParseUint(digits, 10, 0)
+	if err != nil {
+		return 0, errors.New("could not parse count: " + digits)
+	}
+	return uint(count), nil
+}
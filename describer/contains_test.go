@@ -0,0 +1,60 @@
+package describer
+
+import "testing"
+
+func Test_parseContains(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "exact match",
+			output: []byte("v0.3.0^0\n"),
+			want:   "v0.3.0",
+		},
+		{
+			name:   "typical case",
+			output: []byte("v0.3.0~10\n"),
+			want:   "v0.3.0",
+		},
+		{
+			name:   "merge parent traversal",
+			output: []byte("v0.3.0~2^2~3"),
+			want:   "v0.3.0",
+		},
+		{
+			name:   "prerelease semver tag",
+			output: []byte("v1.0.0-rc2~1"),
+			want:   "v1.0.0-rc2",
+		},
+		{
+			name:   "contains --all",
+			output: []byte("tags/v0.3.0~10"),
+			want:   "tags/v0.3.0",
+		},
+		{
+			name:    "empty",
+			output:  []byte("\n"),
+			wantErr: true,
+		},
+		{
+			name:    "no tag",
+			output:  []byte("~10"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseContains(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseContains() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseContains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
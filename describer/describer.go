@@ -9,33 +9,37 @@ import (
 	"os/exec"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/mroth/semverdesc"
 	"github.com/mroth/semverdesc/localgit"
 )
 
 // Options that can adjust the  describe operation.
+//
+// When encoded as JSON, the keys are the snake_case field names, and policies
+// are encoded by name.
 type Options struct {
 	// Instead of using only the annotated tags, use any ref found in refs/
 	// namespace. This option enables matching any known branch, remote-tracking
 	// branch, or lightweight tag.
-	All bool
+	All bool `json:"all"`
 
 	// Instead of using only the annotated tags, use any tag found in
 	// refs/tags namespace. This option enables matching a lightweight
 	// (non-annotated) tag.
-	Tags bool
+	Tags bool `json:"tags"`
 
 	// Instead of considering only the 10 most recent tags as candidates to
 	// describe the input commit-ish consider up to <n> candidates.
 	// Increasing <n> above 10 will take slightly longer but may produce a
 	// more accurate result. An <n> of 0 will cause only exact matches to be
 	// output.
-	Candidates uint
+	Candidates uint `json:"candidates"`
 
 	// Only output exact matches (a tag directly references the supplied
 	// commit). This is a synonym for --candidates=0.
-	ExactMatch bool
+	ExactMatch bool `json:"exact_match"`
 
 	// Only consider tags matching the given glob(7) pattern, excluding the
 	// "refs/tags/" prefix. If used with --all, it also considers local
@@ -45,7 +49,7 @@ type Options struct {
 	// times, a list of patterns will be accumulated, and tags matching any
 	// of the patterns will be considered. Use --no-match to clear and reset
 	// the list of patterns.
-	MatchPattern string
+	MatchPattern string `json:"match_pattern"`
 
 	// Do not consider tags matching the given glob(7) pattern, excluding the
 	// "refs/tags/" prefix. If used with --all, it also does not consider
@@ -57,31 +61,152 @@ type Options struct {
 	// be considered when it matches at least one --match pattern and does
 	// not match any of the --exclude patterns. Use --no-exclude to clear and
 	// reset the list of patterns.
-	ExcludePattern string
+	ExcludePattern string `json:"exclude_pattern"`
 
 	// Follow only the first parent commit upon seeing a merge commit. This
 	// is useful when you wish to not match tags on branches merged in the
 	// history of the target commit.
-	FirstParent bool
+	FirstParent bool `json:"first_parent"`
+
+	// If a repository is corrupt and git cannot determine if there is local
+	// modification, git will error out, unless Broken is set, in which case
+	// the results are marked as Broken instead. Only applies when describing
+	// the working tree.
+	Broken bool `json:"broken"`
+
+	// Selection is the policy used to choose between multiple candidate tags.
+	// The zero value is git's own choice.
+	Selection SelectionPolicy `json:"selection"`
+
+	// Only consider tags which parse as SemVer 2.0, optionally prefixed with
+	// a "v". Since branches are never SemVer, combined with All this only
+	// considers all tags, as Tags would.
+	SemverOnly bool `json:"semver_only"`
+
+	// Only consider tags beginning with TagPrefix, which is then stripped from
+	// the resulting TagName, e.g. for monorepos tagging releases of each
+	// component as "services/billing/v1.4.2". Match and exclude patterns are
+	// relative to the prefix. Combined with All, only tags are considered, as
+	// with Tags.
+	TagPrefix string `json:"tag_prefix"`
+
+	// Only count commits which touch the given pathspecs towards Distance,
+	// with HashStr being the last such commit (or the tagged commit, if there
+	// are none since), so that the results only change when those paths do,
+	// e.g. for a single component in a monorepo.
+	Paths []string `json:"paths"`
+
+	// Describe the Go module whose go.mod is located at the path, following
+	// Go's conventions for its release tags: the TagPrefix is derived from
+	// the module's subdirectory in the repository, and only SemverOnly tags
+	// with a "v" prefix and the major version required by the module path are
+	// considered. If the selected tag would contradict the module path anyway,
+	// a *GoModuleError is returned.
+	GoModule bool `json:"go_module"`
+
+	// When describing submodules with DescribeSubmodules, mark the results of
+	// the superproject as Dirty if any submodule is checked out at a commit
+	// other than the one recorded by the superproject.
+	//
+	// git describe --dirty already counts such a submodule as a local
+	// modification, unless it is ignored by the submodule.<name>.ignore or
+	// diff.ignoreSubmodules configuration, or DirtyIgnore. This marks the
+	// superproject Dirty regardless.
+	DirtySubmodules bool `json:"dirty_submodules"`
+
+	// Treat untracked files (which are not ignored) as local modifications
+	// when describing the working tree.
+	DirtyUntracked bool `json:"dirty_untracked"`
+
+	// Ignore local modifications to paths matching the given pathspecs when
+	// describing the working tree, e.g. "vendor/" or "*.pb.go".
+	DirtyIgnore []string `json:"dirty_ignore"`
+
+	// Ignore changes to submodules when describing the working tree.
+	DirtyIgnoreSubmodules bool `json:"dirty_ignore_submodules"`
+
+	// Compute the DirtyHash of the results when the working tree is dirty.
+	DirtyHash bool `json:"dirty_hash"`
+
+	// How to describe a shallow clone, whose history may be incomplete.
+	Shallow ShallowPolicy `json:"shallow"`
+
+	// The tag name to use when describing a shallow clone fails, with the
+	// ShallowFallback policy, e.g. "v0.0.0".
+	FallbackVersion string `json:"fallback_version"`
+
+	// Do not check the working tree for local modifications when describing
+	// it, so that the results are never Dirty or Broken, e.g. when they will
+	// be formatted without a DirtyMark.
+	SkipDirty bool `json:"skip_dirty"`
+
+	// Cache the results on disk within the git directory, so that describing
+	// again before HEAD or any refs change avoids the git describe operation.
+	// The state of the working tree is still checked each time, unless
+	// SkipDirty is set, in which case cached results are returned without
+	// running git at all.
+	Cache bool `json:"cache"`
+
+	// the resolved Go module, when GoModule is set
+	goModule *goModule
+
+	// whether to run git describe with debug output, as needed by explain
+	debug bool
+
+	// tags git matched which are not acceptable for SemverOnly, excluded when
+	// searching again
+	rejected []string
+}
+
+// Validate returns a descriptive error if the options contain a combination
+// which is contradictory, or in which some of the options would be ignored.
+func (opts Options) Validate() error {
+	switch {
+	case opts.ExactMatch && opts.Candidates != 0 && opts.Candidates != DefaultCandidatesOption:
+		return errors.New("exact match is incompatible with candidates, as it implies no candidates")
+	case opts.Selection.String() != selectionPolicyNames[opts.Selection]:
+		return errors.New("unknown selection policy: " + opts.Selection.String())
+	case opts.Shallow.String() != shallowPolicyNames[opts.Shallow]:
+		return errors.New("unknown shallow policy: " + opts.Shallow.String())
+	case opts.FallbackVersion != "" && opts.Shallow != ShallowFallback:
+		return errors.New("a fallback version requires the fallback shallow policy")
+	case opts.Shallow == ShallowFallback && opts.FallbackVersion == "":
+		return errors.New("the fallback shallow policy requires a fallback version")
+	case opts.DirtyIgnoreSubmodules && opts.DirtySubmodules:
+		return errors.New("treating submodules as dirty is incompatible with ignoring them")
+	case opts.SkipDirty && (opts.Broken || opts.DirtySubmodules || opts.DirtyUntracked ||
+		len(opts.DirtyIgnore) > 0 || opts.DirtyIgnoreSubmodules || opts.DirtyHash):
+		return errors.New("skipping the dirty check is incompatible with options for it")
+	}
+	return nil
+}
+
+// resolveOptions returns opts with any options which depend on the repository
+// located at path resolved, after validating them.
+func resolveOptions(path string, opts Options) (Options, error) {
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	if opts.GoModule && opts.goModule == nil {
+		return resolveGoModule(path, opts)
+	}
+	return opts, nil
 }
 
 /*
 There are a few git describe related flags not currently implemented in
 describer.
 
-TODO: maybe --debug?
-
 WONTFIX: --always, unless strongly requested. Having a fallback like this is
 incompatible with --long and thus makes parsing more complicated (we could also
 just implement ourselves.)
 
-WONTFIX: --broken, unless requested.  I dont think I've ever seen this used and
-complicates parsing.
+NOTE: --contains is a totally different weird format, e.g. v0.3.0~10 is ten
+commits prior to v0.3.0, so rather than an option here it is handled separately
+by DescribeContains, which has its own result type.
 
-WONTFIX: --contains. This is a totally different weird format, e.g. v0.3.0~10 is
-ten commits prior to v0.3.0. I don't believe it currently fits in with this use
-case so lets remove it instead of trying to figure out how to parse and
-translate.
+NOTE: --debug output is never passed through, but parsed by Explain to report
+the candidates git considered.
 */
 
 // DefaultCandidatesOption is the suggested default value for *Options.Candidates
@@ -96,56 +221,197 @@ const DefaultCandidatesOption = uint(10)
 // error condition returned from the underlying git describe command. You can
 // check for this to handle the output differently!
 func Describe(path, commitish string, opts Options) (*semverdesc.DescribeResults, error) {
-	cmd := buildCmd(path, commitish, opts)
-	output, err := cmd.Output()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		cache *describeCache
+		key   string
+		err   error
+	)
+	if opts.Cache {
+		// the cache is only an optimization, so describe as usual if it is
+		// unavailable for any reason
+		if key, err = cacheKey(path, commitish, opts); err == nil {
+			cache, _ = loadCache(path)
+		}
+	}
+
+	d, cached := cache.get(key)
+	if !cached {
+		if d, err = describeCommit(path, commitish, opts); err != nil {
+			return nil, err
+		}
+		cache.put(key, d)
+	}
+
+	if commitish == "" && !opts.SkipDirty && (cached || usesStatusDirty(opts)) {
+		if d.Dirty, d.Broken, err = statusDirty(path, opts); err != nil {
+			return nil, err
+		}
+	}
+	if commitish == "" && d.Dirty && opts.DirtyHash {
+		if d.DirtyHash, err = dirtyHash(path, opts); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// describeCommit performs the describe operation for Describe, other than
+// determining the state of the working tree when checked separately.
+func describeCommit(path, commitish string, opts Options) (*semverdesc.DescribeResults, error) {
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	shallow, err := isShallow(path)
+	if err != nil {
+		return nil, err
+	}
+	if shallow && opts.Shallow == ShallowError {
+		return nil, &ShallowRepositoryError{}
+	}
+
+	var d *semverdesc.DescribeResults
+	if opts.Selection == SelectHighestPrecedence {
+		d, err = describeHighestPrecedence(path, commitish, opts)
+	} else {
+		d, err = describe(path, commitish, opts)
+	}
 	if err != nil {
+		if shallow {
+			return describeShallowFallback(path, commitish, err, opts)
+		}
+		return nil, err
+	}
+	if err := finishResults(path, d, opts); err != nil {
 		return nil, err
 	}
+	d.Approximate = shallow
+	return d, nil
+}
 
-	return parsePDescribe(output)
+// finishResults applies the options which adjust the results of the
+// underlying git describe operation.
+func finishResults(path string, d *semverdesc.DescribeResults, opts Options) error {
+	if len(opts.Paths) > 0 {
+		if err := scopeToPaths(path, d, opts); err != nil {
+			return err
+		}
+	}
+	trimTagPrefix(d, opts)
+	return checkGoModule(d.TagName, opts)
+}
+
+// describe performs a git describe operation using git's own selection.
+func describe(path, commitish string, opts Options) (*semverdesc.DescribeResults, error) {
+	var commitishes []string
+	if commitish != "" {
+		commitishes = append(commitishes, commitish)
+	}
+	for i := 0; i < maxSearches; i++ {
+		cmd, err := buildCmd(path, commitishes, opts)
+		if err != nil {
+			return nil, err
+		}
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		d, err := parsePDescribe(output)
+		if err != nil || acceptsTag(d.TagName, opts) {
+			return d, err
+		}
+		// git chose a tag which only looks like a version, so search again
+		// without it, or any other such tag it could have chosen
+		if opts, err = rejectAll(path, "--merged="+d.HashStr, opts); err != nil {
+			return nil, err
+		}
+	}
+	return nil, errTooManySearches
+}
+
+// trimTagPrefix strips the TagPrefix of opts from the tag names in d.
+func trimTagPrefix(d *semverdesc.DescribeResults, opts Options) {
+	if opts.TagPrefix == "" {
+		return
+	}
+	d.TagName = strings.TrimPrefix(d.TagName, opts.TagPrefix)
+	for i, a := range d.Alternatives {
+		d.Alternatives[i] = strings.TrimPrefix(a, opts.TagPrefix)
+	}
 }
 
 // Options used to get predictable formatting out of the underlying localgit
 // describe operation, so that we can parse it in a reasoned way.
 const (
-	pAbbrev    = uint(40)
-	pDirtyMark = "-dirty"
-	pLong      = true
+	pAbbrev     = uint(40)
+	pDirtyMark  = "-dirty"
+	pBrokenMark = "-broken"
+	pLong       = true
 )
 
 // buildCmd creates the localgit shell command to do the describe and return
-// our predictable output.
-func buildCmd(path, commitish string, opts Options) *exec.Cmd {
+// our predictable output. When no commitishes are given, the working tree is
+// described.
+func buildCmd(path string, commitishes []string, opts Options) (*exec.Cmd, error) {
+	match, exclude := searchPatterns(opts)
+	all, tags := searchRefs(opts)
+
 	gdOpts := localgit.DescribeOptions{
 		// DescribeOptions for the search are passed along directly
-		All:            opts.All,
-		Tags:           opts.Tags,
-		Candidates:     opts.Candidates,
-		ExactMatch:     opts.ExactMatch,
-		MatchPattern:   opts.MatchPattern,
-		ExcludePattern: opts.ExcludePattern,
-		FirstParent:    opts.FirstParent,
+		All:             all,
+		Tags:            tags,
+		Candidates:      opts.Candidates,
+		ExactMatch:      opts.ExactMatch,
+		MatchPatterns:   match,
+		ExcludePatterns: exclude,
+		FirstParent:     opts.FirstParent,
+		// The candidates git considered, as needed for explanations and
+		// selection policies other than git's own, are only exposed via
+		// debug output.
+		Debug: opts.debug,
 		// On the other hand, formatting options we set explicitly to make the
 		// output predictable and parse it later.
-		Abbrev:    pAbbrev,
-		Long:      pLong,
-		DirtyMark: pDirtyMark,
+		Abbrev: pAbbrev,
+		Long:   pLong,
+	}
+	// git refuses to check the working tree state when given a commit-ish,
+	// and some options require checking it ourselves instead.
+	if len(commitishes) == 0 && !opts.SkipDirty && !usesStatusDirty(opts) {
+		gdOpts.DirtyMark = pDirtyMark
+		if opts.Broken {
+			gdOpts.BrokenMark = pBrokenMark
+		}
+	}
+	if err := gdOpts.ValidateArgs(commitishes); err != nil {
+		return nil, err
+	}
+	if err := gdOpts.Check(); err != nil {
+		return nil, err
 	}
 
 	args := []string{"describe"}
 	args = append(args, gdOpts.Flags()...)
-	if commitish != "" {
-		args = append(args, commitish)
-	}
+	args = append(args, commitishes...)
+	return gitCmd(path, args...), nil
+}
+
+// gitCmd creates a git shell command to be executed against the repository
+// located at path.
+func gitCmd(path string, args ...string) *exec.Cmd {
 	cmd := exec.Command("git", args...)
-	// git describe assumes working directory, so we just set that based on path :-)
+	// git assumes working directory, so we just set that based on path :-)
 	cmd.Dir = path
 	return cmd
 }
 
 // regex to match git describe output when predictable format options applied
 var pdescRegex = regexp.MustCompile(
-	fmt.Sprintf(`^(.+)-(\d+)-g([0-9a-f]{%d})(%s)?$`, pAbbrev, pDirtyMark),
+	fmt.Sprintf(`^(.+)-(\d+)-g([0-9a-f]{%d})(%s|%s)?$`,
+		pAbbrev, pDirtyMark, pBrokenMark),
 )
 
 // parsePDescribe parses our "predictable" describe as defined by our
@@ -160,8 +426,9 @@ func parsePDescribe(output []byte) (*semverdesc.DescribeResults, error) {
 		return nil, errors.New("unable to match: [" + string(output) + "]")
 	}
 
-	// if we ended in `-dirty`, last match group will not be empty
-	dirty := len(match[4]) != 0
+	// if we ended in `-dirty` or `-broken`, last match group will not be empty
+	dirty := string(match[4]) == pDirtyMark
+	broken := string(match[4]) == pBrokenMark
 	// sha is the pAbbrev hex chars prior to that, but after the `-g`
 	sha := match[3]
 	// the distance is a series of digits
@@ -178,5 +445,6 @@ func parsePDescribe(output []byte) (*semverdesc.DescribeResults, error) {
 		Distance: uint(distance),
 		HashStr:  string(sha),
 		Dirty:    dirty,
+		Broken:   broken,
 	}, nil
 }
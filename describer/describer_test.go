@@ -69,6 +69,17 @@ func Test_parsePDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "broken repository",
+			output: []byte("v1.2.3-13-g56dc2041f2c45ab15d41e63058c1c44fff905e81-broken"),
+			want: &semverdesc.DescribeResults{
+				TagName:  "v1.2.3",
+				Distance: 13,
+				HashStr:  "56dc2041f2c45ab15d41e63058c1c44fff905e81",
+				Broken:   true,
+			},
+			wantErr: false,
+		},
 		{
 			name:   "describe -all and branch with a dash",
 			output: []byte("refs/heads/aruba-update-2-g56dc2041f2c45ab15d41e63058c1c44fff905e81"),
@@ -100,3 +111,65 @@ func Test_parsePDescribe(t *testing.T) {
 		})
 	}
 }
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			opts: Options{Candidates: DefaultCandidatesOption},
+		},
+		{
+			name: "exact match with default candidates",
+			opts: Options{ExactMatch: true, Candidates: DefaultCandidatesOption},
+		},
+		{
+			name:    "exact match with candidates",
+			opts:    Options{ExactMatch: true, Candidates: 3},
+			wantErr: true,
+		},
+		{
+			name:    "unknown selection policy",
+			opts:    Options{Selection: SelectionPolicy(42)},
+			wantErr: true,
+		},
+		{
+			name: "shallow fallback",
+			opts: Options{Shallow: ShallowFallback, FallbackVersion: "v0.0.0"},
+		},
+		{
+			name:    "shallow fallback without version",
+			opts:    Options{Shallow: ShallowFallback},
+			wantErr: true,
+		},
+		{
+			name:    "fallback version without policy",
+			opts:    Options{FallbackVersion: "v0.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "contradictory submodule options",
+			opts:    Options{DirtySubmodules: true, DirtyIgnoreSubmodules: true},
+			wantErr: true,
+		},
+		{
+			name: "skip dirty",
+			opts: Options{SkipDirty: true, Cache: true},
+		},
+		{
+			name:    "skip dirty with dirty options",
+			opts:    Options{SkipDirty: true, DirtyUntracked: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
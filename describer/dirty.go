@@ -0,0 +1,151 @@
+package describer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mroth/semverdesc/localgit"
+)
+
+// usesStatusDirty reports whether opts require the state of the working tree
+// to be determined via git status, as git describe --dirty is too coarse, or
+// git describe --broken is unavailable in the installed git.
+func usesStatusDirty(opts Options) bool {
+	return opts.DirtyUntracked || len(opts.DirtyIgnore) > 0 || opts.DirtyIgnoreSubmodules ||
+		(opts.Broken && !brokenSupported())
+}
+
+// brokenSupported reports whether the installed git supports git describe
+// --broken, assuming it does if the version can't be determined so that any
+// underlying problem is reported by git itself.
+func brokenSupported() bool {
+	ok, err := localgit.HasVersion(localgit.VersionDescribeBroken)
+	return ok || err != nil
+}
+
+// statusDirty determines whether the working tree of the repository located at
+// path has local modifications, according to the dirty options of opts.
+//
+// If git is unable to determine the state of the working tree and opts has
+// Broken set, the working tree is reported as broken rather than returning the
+// error, as git describe --broken would.
+func statusDirty(path string, opts Options) (dirty, broken bool, err error) {
+	output, err := gitCmd(path, statusArgs(opts)...).Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok && opts.Broken {
+			return false, true, nil
+		}
+		return false, false, err
+	}
+	return len(bytes.TrimSpace(output)) > 0, false, nil
+}
+
+// statusArgs returns the git status arguments listing the local modifications
+// which count towards the working tree being dirty for opts.
+func statusArgs(opts Options) []string {
+	args := []string{"status", "--porcelain"}
+	if opts.DirtyUntracked {
+		args = append(args, "--untracked-files=all")
+	} else {
+		args = append(args, "--untracked-files=no")
+	}
+	if opts.DirtyIgnoreSubmodules {
+		args = append(args, "--ignore-submodules=all")
+	}
+	return append(args, dirtyPathspecs(opts)...)
+}
+
+// dirtyHash computes a hash of the local modifications to the working tree of
+// the repository located at path, which is stable for identical modifications.
+// It covers the diff of tracked files against HEAD, along with the names and
+// contents of untracked files when opts has DirtyUntracked set.
+func dirtyHash(path string, opts Options) (string, error) {
+	h := sha256.New()
+	diff, err := gitCmd(path, diffArgs(opts)...).Output()
+	if err != nil {
+		return "", err
+	}
+	h.Write(diff)
+
+	if opts.DirtyUntracked {
+		top, err := gitCmd(path, "rev-parse", "--show-toplevel").Output()
+		if err != nil {
+			return "", err
+		}
+		output, err := gitCmd(path, untrackedArgs(opts)...).Output()
+		if err != nil {
+			return "", err
+		}
+		for _, name := range bytes.Split(output, []byte{0}) {
+			if len(name) == 0 {
+				continue
+			}
+			file := filepath.Join(string(bytes.TrimSpace(top)), string(name))
+			if err := hashUntracked(h, string(name), file); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashUntracked writes the name and contents of an untracked file to h, where
+// the contents of a symbolic link are its target.
+func hashUntracked(h hash.Hash, name, file string) error {
+	info, err := os.Lstat(file)
+	if err != nil {
+		return err
+	}
+	var contents []byte
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(file)
+		if err != nil {
+			return err
+		}
+		contents = []byte(target)
+	} else if contents, err = ioutil.ReadFile(file); err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "%s\x00%d\x00", name, len(contents))
+	h.Write(contents)
+	return nil
+}
+
+// diffArgs returns the git diff arguments outputting the local modifications
+// to tracked files for opts, independent of any diff configuration which would
+// change the output for identical modifications.
+func diffArgs(opts Options) []string {
+	args := []string{"diff", "--binary", "--no-color", "--no-ext-diff",
+		"--no-textconv", "--no-renames", "--src-prefix=a/", "--dst-prefix=b/"}
+	if opts.DirtyIgnoreSubmodules {
+		args = append(args, "--ignore-submodules=all")
+	}
+	args = append(args, "HEAD")
+	return append(args, dirtyPathspecs(opts)...)
+}
+
+// untrackedArgs returns the git ls-files arguments listing the untracked files
+// for opts, NUL separated and relative to the top of the working tree.
+func untrackedArgs(opts Options) []string {
+	args := []string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name"}
+	return append(args, dirtyPathspecs(opts)...)
+}
+
+// dirtyPathspecs returns the pathspecs (preceded by the "--" separator) which
+// limit the local modifications considered for opts: the whole working tree,
+// as git describe --dirty considers even when path is a subdirectory of it,
+// excluding any of DirtyIgnore.
+func dirtyPathspecs(opts Options) []string {
+	args := []string{"--", ":/"}
+	for _, p := range opts.DirtyIgnore {
+		args = append(args, ":(exclude)"+p)
+	}
+	return args
+}
@@ -0,0 +1,73 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_statusArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: Options{},
+			want: []string{"status", "--porcelain", "--untracked-files=no", "--", ":/"},
+		},
+		{
+			name: "untracked",
+			opts: Options{DirtyUntracked: true},
+			want: []string{"status", "--porcelain", "--untracked-files=all", "--", ":/"},
+		},
+		{
+			name: "ignore submodules",
+			opts: Options{DirtyIgnoreSubmodules: true},
+			want: []string{"status", "--porcelain", "--untracked-files=no",
+				"--ignore-submodules=all", "--", ":/"},
+		},
+		{
+			name: "ignore pathspecs",
+			opts: Options{DirtyIgnore: []string{"vendor/", "*.pb.go"}},
+			want: []string{"status", "--porcelain", "--untracked-files=no", "--", ":/",
+				":(exclude)vendor/", ":(exclude)*.pb.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusArgs(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statusArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diffArgs(t *testing.T) {
+	base := []string{"diff", "--binary", "--no-color", "--no-ext-diff",
+		"--no-textconv", "--no-renames", "--src-prefix=a/", "--dst-prefix=b/"}
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: Options{},
+			want: append(base[:len(base):len(base)], "HEAD", "--", ":/"),
+		},
+		{
+			name: "ignore submodules and pathspecs",
+			opts: Options{DirtyIgnoreSubmodules: true, DirtyIgnore: []string{"vendor/"}},
+			want: append(base[:len(base):len(base)], "--ignore-submodules=all", "HEAD",
+				"--", ":/", ":(exclude)vendor/"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffArgs(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package describer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+)
+
+// Candidate is a tag considered by git when describing a commit.
+type Candidate struct {
+	// The name of the tag, as it would be output by describe
+	TagName string
+	// The kind of ref, e.g. "annotated", "lightweight" or "head"
+	Type string
+	// Number of commits between the tag and the described commit
+	Depth uint
+}
+
+// Explanation details how the results of a describe were arrived at.
+type Explanation struct {
+	// The results of the describe
+	Results *semverdesc.DescribeResults
+	// The selection policy which chose the tag from the candidates
+	Selection SelectionPolicy
+	// ExactMatch is true if a tag points directly at the commit, in which
+	// case git does not search the history and the Candidates are all of the
+	// tags pointing at it.
+	ExactMatch bool
+	// The candidate tags, in the order of git's preference: nearest first,
+	// with ties broken by the most recent tag date.
+	Candidates []Candidate
+	// Number of commits git traversed in its search
+	Traversed uint
+	// GaveUp is true if git stopped its search upon finding as many
+	// candidates as allowed by the Candidates option, so nearer tags may
+	// have been missed.
+	GaveUp bool
+}
+
+// Explain performs a git describe operation in the same way as Describe, but
+// also returns the candidate tags git considered and how one was chosen, to
+// help understand why a commit was described the way it was.
+//
+// As with Describe, the returned error may be of type exec.ExitError.
+func Explain(path, commitish string, opts Options) (*Explanation, error) {
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	e, err := explain(path, commitish, opts)
+	if err != nil {
+		return nil, err
+	}
+	if commitish == "" && usesStatusDirty(opts) {
+		if e.Results.Dirty, e.Results.Broken, err = statusDirty(path, opts); err != nil {
+			return nil, err
+		}
+	}
+	if err := finishResults(path, e.Results, opts); err != nil {
+		return nil, err
+	}
+	for i, c := range e.Candidates {
+		e.Candidates[i].TagName = strings.TrimPrefix(c.TagName, opts.TagPrefix)
+	}
+	return e, nil
+}
+
+// explain performs a describe with debug output, selecting the tag according
+// to the selection policy of opts.
+func explain(path, commitish string, opts Options) (*Explanation, error) {
+	var commitishes []string
+	if commitish != "" {
+		commitishes = append(commitishes, commitish)
+	}
+	opts.debug = true
+	if opts.Selection == SelectHighestPrecedence {
+		// only the nearest SemVer tags are candidates, so search for them
+		// alone rather than settling for a nearer tag which is not SemVer
+		opts.SemverOnly = true
+	}
+	for i := 0; i < maxSearches; i++ {
+		e, err := explainOnce(path, commitishes, opts)
+		if err != nil {
+			return nil, err
+		}
+		// git chose a tag which only looks like a version, so search again
+		// without it, or any other such tag it could have chosen
+		if !acceptsTag(e.Results.TagName, opts) {
+			if opts, err = rejectAll(path, "--merged="+e.Results.HashStr, opts); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var candidates []Candidate
+		for _, c := range e.Candidates {
+			if acceptsTag(c.TagName, opts) {
+				candidates = append(candidates, c)
+			}
+		}
+		e.Candidates = candidates
+
+		if opts.Selection == SelectHighestPrecedence {
+			if !e.ExactMatch {
+				if e.Candidates, err = addSiblingCandidates(path, e.Candidates, e.Results.Distance, opts); err != nil {
+					return nil, err
+				}
+			}
+			e.Results.TagName, e.Results.Alternatives = selectHighestPrecedence(e.Results, e.Candidates, opts)
+		}
+		return e, nil
+	}
+	return nil, errTooManySearches
+}
+
+// explainOnce performs a single describe with debug output, with the results
+// as chosen by git.
+func explainOnce(path string, commitishes []string, opts Options) (*Explanation, error) {
+	cmd, err := buildCmd(path, commitishes, opts)
+	if err != nil {
+		return nil, err
+	}
+	output, debug, err := runDebug(cmd)
+	if err != nil {
+		return nil, err
+	}
+	d, err := parsePDescribe(output)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseDebug(debug)
+	if err != nil {
+		return nil, err
+	}
+	e.Results, e.Selection = d, opts.Selection
+
+	// An exact match short-circuits the candidate search in git, so instead
+	// consider all the tags which point at the commit.
+	if d.Distance == 0 {
+		e.ExactMatch = true
+		if e.Candidates, err = pointsAt(path, d.HashStr, opts); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// regexes to match the summary lines of git describe debug output
+var (
+	debugTraversedRegex = regexp.MustCompile(`^traversed (\d+) commits$`)
+	debugGaveUpRegex    = regexp.MustCompile(`^gave up search at `)
+)
+
+// parseDebug parses git describe --debug output into an Explanation, without
+// any Results.
+func parseDebug(debug []byte) (*Explanation, error) {
+	candidates, err := parseDebugCandidates(debug)
+	if err != nil {
+		return nil, err
+	}
+	e := &Explanation{Candidates: candidates}
+
+	scanner := bufio.NewScanner(bytes.NewReader(debug))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := debugTraversedRegex.FindStringSubmatch(line); match != nil {
+			traversed, err := strconv.Atoi(match[1])
+			if err != nil {
+				return nil, errors.New("could not parse traversed: " + match[1])
+			}
+			e.Traversed = uint(traversed)
+		} else if debugGaveUpRegex.MatchString(line) {
+			e.GaveUp = true
+		}
+	}
+	return e, scanner.Err()
+}
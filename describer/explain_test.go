@@ -0,0 +1,65 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseDebug(t *testing.T) {
+	tests := []struct {
+		name  string
+		debug []byte
+		want  *Explanation
+	}{
+		{
+			name: "search",
+			debug: []byte(`describe HEAD
+No exact match on refs or tags, searching to describe
+finished search at db5aad4086e89f1af137574a56f4cf17543f3f46
+ annotated          2 v1.1.0
+ lightweight        5 latest
+traversed 8 commits
+`),
+			want: &Explanation{
+				Candidates: []Candidate{
+					{TagName: "v1.1.0", Type: "annotated", Depth: 2},
+					{TagName: "latest", Type: "lightweight", Depth: 5},
+				},
+				Traversed: 8,
+			},
+		},
+		{
+			name: "gave up",
+			debug: []byte(`describe HEAD
+No exact match on refs or tags, searching to describe
+ annotated          2 v1.2.0
+more than 1 tags found; listed 1 most recent
+gave up search at 3d0a28e1a3c5bd2e5d1c2a24d8e4e8a5d2b6f7c9
+traversed 3 commits
+`),
+			want: &Explanation{
+				Candidates: []Candidate{
+					{TagName: "v1.2.0", Type: "annotated", Depth: 2},
+				},
+				Traversed: 3,
+				GaveUp:    true,
+			},
+		},
+		{
+			name:  "exact match",
+			debug: []byte("describe HEAD\n"),
+			want:  &Explanation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDebug(tt.debug)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDebug() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
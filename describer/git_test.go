@@ -0,0 +1,40 @@
+package describer
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// gitRepo creates a git repository with a single empty commit in a temporary
+// directory, returning the path of its working tree, which the caller should
+// remove when done. The test is skipped if git is unavailable.
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir, err := ioutil.TempDir("", "semverdesc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "init", "--quiet")
+	git(t, dir, "commit", "--quiet", "--allow-empty", "--message", "initial")
+	return dir
+}
+
+// git runs a git command in repo, failing the test if it does not succeed,
+// and returns its output without any trailing newline.
+func git(t *testing.T, repo string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com",
+		"-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimRight(string(output), "\n")
+}
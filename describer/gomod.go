@@ -0,0 +1,167 @@
+package describer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// GoModuleError is returned when the tag selected for a Go module contradicts
+// the major version of its module path.
+type GoModuleError struct {
+	// The module path, as declared in go.mod
+	ModulePath string
+	// The tag which was selected
+	TagName string
+}
+
+func (e *GoModuleError) Error() string {
+	return fmt.Sprintf("tag %v does not match the major version of module %v",
+		e.TagName, e.ModulePath)
+}
+
+// goModule is the information from a go.mod relevant to its release tags.
+type goModule struct {
+	// The module path, as declared in go.mod
+	Path string
+	// The major version required of tags, as returned by moduleMajor
+	Major uint64
+}
+
+// resolveGoModule returns opts adjusted for the Go module located at path.
+//
+// Go requires release tags for a module nested in a subdirectory of the
+// repository to be prefixed with that subdirectory (excluding any major version
+// subdirectory), and the major version of the tag to match the major version
+// suffix of the module path, e.g. "example.com/mod/v2" must be tagged v2.x.y.
+func resolveGoModule(path string, opts Options) (Options, error) {
+	gomod, err := ioutil.ReadFile(filepath.Join(path, "go.mod"))
+	if err != nil {
+		return opts, err
+	}
+	modPath, err := parseModulePath(gomod)
+	if err != nil {
+		return opts, err
+	}
+	output, err := gitCmd(path, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return opts, err
+	}
+
+	mod := &goModule{Path: modPath, Major: moduleMajor(modPath)}
+	prefix := moduleTagPrefix(string(bytes.TrimSpace(output)), mod.Major)
+	if opts.TagPrefix != "" && opts.TagPrefix != prefix {
+		return opts, fmt.Errorf("tag prefix %q contradicts prefix %q required by module %v",
+			opts.TagPrefix, prefix, modPath)
+	}
+	opts.TagPrefix = prefix
+	opts.SemverOnly = true
+	opts.goModule = mod
+	return opts, nil
+}
+
+// ResolveTagPrefix returns the TagPrefix which opts describe the git
+// repository located at path with, i.e. that required by its Go module if
+// GoModule is set, for naming a tag which the describe would find.
+func ResolveTagPrefix(path string, opts Options) (string, error) {
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return "", err
+	}
+	return opts.TagPrefix, nil
+}
+
+// checkGoModule verifies the tag name (without TagPrefix) is acceptable for
+// the Go module of opts, if any.
+func checkGoModule(tag string, opts Options) error {
+	if opts.goModule != nil && !opts.goModule.accepts(tag) {
+		return &GoModuleError{ModulePath: opts.goModule.Path, TagName: opts.TagPrefix + tag}
+	}
+	return nil
+}
+
+// accepts reports whether the tag version is a valid version for the module.
+func (mod *goModule) accepts(version string) bool {
+	v, err := semver.Parse(version)
+	if err != nil || v.Prefix != "v" {
+		return false
+	}
+	if mod.Major == 1 {
+		return v.Major == 0 || v.Major == 1
+	}
+	return v.Major == mod.Major
+}
+
+// parseModulePath returns the module path declared in the contents of a
+// go.mod file.
+func parseModulePath(gomod []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(gomod))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+		modPath := fields[1]
+		if unquoted, err := strconv.Unquote(modPath); err == nil {
+			modPath = unquoted
+		}
+		return modPath, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("no module directive found in go.mod")
+}
+
+// moduleMajor returns the major version required by the suffix of a module
+// path, e.g. 2 for "example.com/mod/v2" or "gopkg.in/yaml.v2". A module path
+// without a major version suffix returns 1, as it accepts v0 or v1.
+func moduleMajor(modPath string) uint64 {
+	sep := "/v"
+	if strings.HasPrefix(modPath, "gopkg.in/") {
+		sep = ".v"
+	}
+	i := strings.LastIndex(modPath, sep)
+	if i == -1 {
+		return 1
+	}
+	suffix := modPath[i+len(sep):]
+	if suffix == "" || suffix[0] == '0' {
+		return 1
+	}
+	major, err := strconv.ParseUint(suffix, 10, 64)
+	if err != nil || major < 1 {
+		return 1
+	}
+	return major
+}
+
+// moduleTagPrefix returns the tag prefix for a module located in the
+// repository subdirectory dir (as output by `git rev-parse --show-prefix`),
+// which omits a major version subdirectory matching the module major version.
+func moduleTagPrefix(dir string, major uint64) string {
+	dir = strings.TrimSuffix(dir, "/")
+	if major >= 2 {
+		majorDir := "v" + strconv.FormatUint(major, 10)
+		if dir == majorDir {
+			dir = ""
+		} else {
+			dir = strings.TrimSuffix(dir, "/"+majorDir)
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	return dir + "/"
+}
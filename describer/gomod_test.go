@@ -0,0 +1,151 @@
+package describer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_parseModulePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		gomod   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "typical",
+			gomod: "module github.com/mroth/semverdesc\n\ngo 1.12\n",
+			want:  "github.com/mroth/semverdesc",
+		},
+		{
+			name:  "quoted with comment",
+			gomod: "// a comment\nmodule \"example.com/mod/v2\" // trailing\n",
+			want:  "example.com/mod/v2",
+		},
+		{
+			name:    "missing",
+			gomod:   "go 1.12\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseModulePath([]byte(tt.gomod))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseModulePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseModulePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_moduleMajor(t *testing.T) {
+	tests := []struct {
+		modPath string
+		want    uint64
+	}{
+		{modPath: "github.com/mroth/semverdesc", want: 1},
+		{modPath: "example.com/mod/v2", want: 2},
+		{modPath: "example.com/mod/v10", want: 10},
+		{modPath: "example.com/v2ray", want: 1},
+		{modPath: "example.com/mod/v0", want: 1},
+		{modPath: "gopkg.in/yaml.v3", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modPath, func(t *testing.T) {
+			if got := moduleMajor(tt.modPath); got != tt.want {
+				t.Errorf("moduleMajor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_moduleTagPrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		major uint64
+		want  string
+	}{
+		{name: "repository root", dir: "", major: 1, want: ""},
+		{name: "nested module", dir: "services/billing/", major: 1, want: "services/billing/"},
+		{name: "major subdirectory", dir: "v2/", major: 2, want: ""},
+		{name: "nested major subdirectory", dir: "services/billing/v3/", major: 3, want: "services/billing/"},
+		{name: "unrelated major subdirectory", dir: "services/v2/", major: 3, want: "services/v2/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moduleTagPrefix(tt.dir, tt.major); got != tt.want {
+				t.Errorf("moduleTagPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_goModule_accepts(t *testing.T) {
+	tests := []struct {
+		name    string
+		major   uint64
+		version string
+		want    bool
+	}{
+		{name: "v0 module", major: 1, version: "v0.3.1", want: true},
+		{name: "v1 module", major: 1, version: "v1.2.3", want: true},
+		{name: "v2 tag for v1 module", major: 1, version: "v2.0.0", want: false},
+		{name: "v2 module", major: 2, version: "v2.0.0-rc.1", want: true},
+		{name: "v1 tag for v2 module", major: 2, version: "v1.9.0", want: false},
+		{name: "missing v prefix", major: 1, version: "1.2.3", want: false},
+		{name: "not semver", major: 1, version: "latest", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mod := &goModule{Path: "example.com/mod", Major: tt.major}
+			if got := mod.accepts(tt.version); got != tt.want {
+				t.Errorf("goModule.accepts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTagPrefix(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	sub := filepath.Join(repo, "tools", "lint")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gomod := []byte("module example.com/repo/tools/lint/v2\n")
+	if err := ioutil.WriteFile(filepath.Join(sub, "go.mod"), gomod, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{name: "no module", path: sub, opts: Options{TagPrefix: "lint/"}, want: "lint/"},
+		{name: "nested module", path: sub, opts: Options{GoModule: true}, want: "tools/lint/"},
+		{name: "agreeing prefix", path: sub, opts: Options{GoModule: true, TagPrefix: "tools/lint/"}, want: "tools/lint/"},
+		{name: "contradicting prefix", path: sub, opts: Options{GoModule: true, TagPrefix: "lint/"}, wantErr: true},
+		{name: "missing go.mod", path: repo, opts: Options{GoModule: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTagPrefix(tt.path, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveTagPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveTagPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package describer
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/mroth/semverdesc"
+)
+
+// LogEntry is a single commit from Log, annotated with its describe results.
+type LogEntry struct {
+	// The full SHA hash of the commit, as a string
+	HashStr string
+	// The subject line of the commit message
+	Subject string
+	// The results of describing the commit, nil if Err is set
+	Results *semverdesc.DescribeResults
+	// Any error encountered while describing the commit
+	Err error
+}
+
+// Log walks the commits in revRange (as understood by `git log`) of a git
+// repository located at path, describing each of them. For the default case
+// (the history of HEAD), set the revRange as the zero value.
+//
+// Entries are returned in the same order as `git log` would show them, and are
+// described in batches via DescribeMany, so per-entry errors are set on the
+// LogEntry rather than returned. The returned error may be of type
+// exec.ExitError if there was an error condition walking the history itself.
+func Log(path, revRange string, opts Options) ([]LogEntry, error) {
+	if revRange == "" {
+		revRange = "HEAD"
+	}
+	output, err := gitCmd(path, "log", "--no-color", "--format=%H%x00%s", revRange, "--").Output()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseLog(output)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(entries))
+	for i, e := range entries {
+		hashes[i] = e.HashStr
+	}
+	for i, r := range DescribeMany(path, hashes, opts) {
+		entries[i].Results, entries[i].Err = r.Results, r.Err
+	}
+	return entries, nil
+}
+
+// parseLog parses the output of git log with a format of "%H%x00%s".
+func parseLog(output []byte) ([]LogEntry, error) {
+	output = bytes.TrimSuffix(output, []byte("\n"))
+	if len(output) == 0 {
+		return nil, nil
+	}
+	lines := bytes.Split(output, []byte("\n"))
+	entries := make([]LogEntry, len(lines))
+	for i, line := range lines {
+		fields := bytes.SplitN(line, []byte{0}, 2)
+		if len(fields) != 2 {
+			return nil, errors.New("unable to match: [" + string(line) + "]")
+		}
+		entries[i] = LogEntry{HashStr: string(fields[0]), Subject: string(fields[1])}
+	}
+	return entries, nil
+}
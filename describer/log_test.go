@@ -0,0 +1,54 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  []byte
+		want    []LogEntry
+		wantErr bool
+	}{
+		{
+			name: "typical case",
+			output: []byte("56dc2041f2c45ab15d41e63058c1c44fff905e81\x00fix the thing\n" +
+				"71dd5072d51458a534ca7e0ec7c181d84754774d\x00add the thing\n"),
+			want: []LogEntry{
+				{HashStr: "56dc2041f2c45ab15d41e63058c1c44fff905e81", Subject: "fix the thing"},
+				{HashStr: "71dd5072d51458a534ca7e0ec7c181d84754774d", Subject: "add the thing"},
+			},
+		},
+		{
+			name:   "empty subject",
+			output: []byte("56dc2041f2c45ab15d41e63058c1c44fff905e81\x00\n"),
+			want: []LogEntry{
+				{HashStr: "56dc2041f2c45ab15d41e63058c1c44fff905e81", Subject: ""},
+			},
+		},
+		{
+			name:   "empty range",
+			output: []byte(""),
+			want:   nil,
+		},
+		{
+			name:    "unexpected format",
+			output:  []byte("56dc2041f2c45ab15d41e63058c1c44fff905e81 fix the thing\n"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLog(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseLog() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLog() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package describer
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mroth/semverdesc"
+)
+
+// Result is the outcome of describing a single commit-ish as part of
+// DescribeMany.
+type Result struct {
+	// The commit-ish which was described
+	Commitish string
+	// The results of the describe, nil if Err is set
+	Results *semverdesc.DescribeResults
+	// Any error encountered while describing this commit-ish
+	Err error
+}
+
+// maxBatchSize is the maximum number of commit-ishes passed to a single git
+// describe invocation, in order to stay well clear of platform limits on
+// command line length.
+const maxBatchSize = 512
+
+// DescribeMany performs a git describe operation for each of the commitishes
+// on a git repository located at path, batching them into as few underlying
+// git invocations as possible.
+//
+// The returned Results are in the same order as the commitishes. Since git
+// describe aborts on the first commit-ish it is unable to describe, a batch
+// containing a failure is retried one commit-ish at a time, so that errors are
+// reported only against the commit-ishes which caused them. As with Describe,
+// those errors may be of type exec.ExitError.
+//
+// Describing the working tree is not possible with DescribeMany, so Broken has
+// no effect and Dirty will never be set in the results.
+func DescribeMany(path string, commitishes []string, opts Options) []Result {
+	results := make([]Result, 0, len(commitishes))
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		for _, c := range commitishes {
+			results = append(results, Result{Commitish: c, Err: err})
+		}
+		return results
+	}
+
+	for start := 0; start < len(commitishes); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(commitishes) {
+			end = len(commitishes)
+		}
+		results = append(results, describeBatch(path, commitishes[start:end], opts)...)
+	}
+	return results
+}
+
+// describeBatch describes a batch of commitishes in a single git invocation,
+// falling back to describing them individually on failure.
+func describeBatch(path string, commitishes []string, opts Options) []Result {
+	// selection policies other than git's own need to consider candidates for
+	// each commit-ish independently, so are never batched, and neither are
+	// shallow repositories so that the ShallowPolicy is applied to each.
+	if shallow, err := isShallow(path); err == nil && !shallow && opts.Selection == SelectGit {
+		if results, err := describeBatched(path, commitishes, opts); err == nil {
+			return results
+		}
+	}
+
+	results := make([]Result, len(commitishes))
+	for i, c := range commitishes {
+		d, err := Describe(path, c, opts)
+		results[i] = Result{Commitish: c, Results: d, Err: err}
+	}
+	return results
+}
+
+// describeBatched describes all of the commitishes in a single git invocation.
+func describeBatched(path string, commitishes []string, opts Options) ([]Result, error) {
+	cmd, err := buildCmd(path, commitishes, opts)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	results, err := parsePDescribeMany(commitishes, output)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		if r.Err == nil && !acceptsTag(r.Results.TagName, opts) {
+			// git chose a tag which only looks like a version, so search
+			// again for this commit-ish alone
+			results[i].Results, results[i].Err = describe(path, r.Commitish, opts)
+		}
+		if results[i].Err != nil {
+			continue
+		}
+		if err := finishResults(path, results[i].Results, opts); err != nil {
+			results[i].Results, results[i].Err = nil, err
+		}
+	}
+	return results, nil
+}
+
+// parsePDescribeMany parses the output of a git describe operation on multiple
+// commitishes, which is one predictable describe per line in the same order.
+func parsePDescribeMany(commitishes []string, output []byte) ([]Result, error) {
+	lines := bytes.Split(bytes.TrimSuffix(output, []byte("\n")), []byte("\n"))
+	if len(lines) != len(commitishes) {
+		return nil, fmt.Errorf("expected %d results, received %d",
+			len(commitishes), len(lines))
+	}
+
+	results := make([]Result, len(commitishes))
+	for i, line := range lines {
+		d, err := parsePDescribe(line)
+		results[i] = Result{Commitish: commitishes[i], Results: d, Err: err}
+	}
+	return results, nil
+}
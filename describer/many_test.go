@@ -0,0 +1,61 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mroth/semverdesc"
+)
+
+func Test_parsePDescribeMany(t *testing.T) {
+	tests := []struct {
+		name        string
+		commitishes []string
+		output      []byte
+		want        []Result
+		wantErr     bool
+	}{
+		{
+			name:        "multiple results in order",
+			commitishes: []string{"HEAD", "HEAD~13"},
+			output: []byte("v1.2.3-13-g56dc2041f2c45ab15d41e63058c1c44fff905e81\n" +
+				"v1.2.3-0-g71dd5072d51458a534ca7e0ec7c181d84754774d\n"),
+			want: []Result{
+				{
+					Commitish: "HEAD",
+					Results: &semverdesc.DescribeResults{
+						TagName:  "v1.2.3",
+						Distance: 13,
+						HashStr:  "56dc2041f2c45ab15d41e63058c1c44fff905e81",
+					},
+				},
+				{
+					Commitish: "HEAD~13",
+					Results: &semverdesc.DescribeResults{
+						TagName:  "v1.2.3",
+						Distance: 0,
+						HashStr:  "71dd5072d51458a534ca7e0ec7c181d84754774d",
+					},
+				},
+			},
+		},
+		{
+			name:        "mismatched number of results",
+			commitishes: []string{"HEAD", "HEAD~13"},
+			output:      []byte("v1.2.3-13-g56dc2041f2c45ab15d41e63058c1c44fff905e81\n"),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePDescribeMany(tt.commitishes, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parsePDescribeMany() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePDescribeMany() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package describer
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/semver"
+)
+
+// Change is the significance of a change, as classified from Conventional
+// Commits. (https://www.conventionalcommits.org)
+type Change int
+
+// Kinds of change, in increasing significance.
+const (
+	// ChangeNone is any commit which is not a fix, feature or breaking change,
+	// including those which do not follow Conventional Commits at all.
+	ChangeNone Change = iota
+	// ChangePatch is a bug fix, i.e. a commit of type "fix".
+	ChangePatch
+	// ChangeMinor is a new feature, i.e. a commit of type "feat".
+	ChangeMinor
+	// ChangeMajor is a breaking change, marked by a "!" after the type or a
+	// "BREAKING CHANGE" footer.
+	ChangeMajor
+)
+
+var changeNames = map[Change]string{
+	ChangeNone:  "none",
+	ChangePatch: "patch",
+	ChangeMinor: "minor",
+	ChangeMajor: "major",
+}
+
+// String returns the name of the change.
+func (c Change) String() string {
+	if name, ok := changeNames[c]; ok {
+		return name
+	}
+	return "Change(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the change by name.
+func (c Change) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// ConventionalCommit is a commit classified per Conventional Commits.
+type ConventionalCommit struct {
+	// The full SHA hash of the commit, as a string
+	HashStr string
+	// The subject line of the commit message
+	Subject string
+	// The type of the commit (e.g. "feat"), in lower case, empty if the
+	// commit does not follow Conventional Commits
+	Type string
+	// The optional scope following the type
+	Scope string
+	// Whether the commit is marked as a breaking change
+	Breaking bool
+	// The significance of the change
+	Change Change
+}
+
+// NextVersion is the next version suggested by Next.
+type NextVersion struct {
+	// The results of describing the commit-ish
+	Results *semverdesc.DescribeResults
+	// The version of the tag in the results
+	Current semver.Version
+	// The suggested next version, the same as Current if no commit since its
+	// tag is a fix, feature or breaking change
+	Next semver.Version
+	// The most significant change of the commits
+	Change Change
+	// The commits since the tag, in the same order as `git log` shows them
+	Commits []ConventionalCommit
+}
+
+// Drivers returns the commits whose change determined the next version.
+func (n *NextVersion) Drivers() []ConventionalCommit {
+	var drivers []ConventionalCommit
+	if n.Change == ChangeNone {
+		return drivers
+	}
+	for _, c := range n.Commits {
+		if c.Change == n.Change {
+			drivers = append(drivers, c)
+		}
+	}
+	return drivers
+}
+
+// Next describes a commit-ish of the git repository located at path (HEAD if
+// commitish is the zero value), then suggests the version following its tag
+// by classifying the commits since then per Conventional Commits: a breaking
+// change bumps the major version, a feature the minor version, and a fix the
+// patch version. The tag must be valid SemVer.
+//
+// While the major version is zero, breaking changes only bump the minor
+// version, since moving to 1.0.0 is a deliberate decision rather than one to
+// derive from history. Bumping a pre-release follows semver.Bump, e.g. a fix
+// after v1.3.0-rc.1 suggests releasing v1.3.0.
+//
+// Only SemVer tags are considered, as SemverOnly does, since a next version is
+// meaningless without a SemVer base, so a nearer tag such as "latest" is
+// passed over. Only commits which would be counted by the describe are
+// considered, so FirstParent and Paths are respected. As with Describe, the
+// returned error may be of type exec.ExitError.
+func Next(path, commitish string, opts Options) (*NextVersion, error) {
+	opts.SemverOnly = true
+	opts, err := resolveOptions(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	d, err := Describe(path, commitish, opts)
+	if err != nil {
+		return nil, err
+	}
+	current, err := semver.Parse(d.TagName)
+	if err != nil {
+		return nil, errors.New(d.TagName + " is not a SemVer tag")
+	}
+
+	commits, err := commitsSince(path, d, opts)
+	if err != nil {
+		return nil, err
+	}
+	change := ChangeNone
+	for _, c := range commits {
+		if c.Change > change {
+			change = c.Change
+		}
+	}
+	next, err := nextVersion(current, change)
+	if err != nil {
+		return nil, err
+	}
+	return &NextVersion{
+		Results: d,
+		Current: current,
+		Next:    next,
+		Change:  change,
+		Commits: commits,
+	}, nil
+}
+
+// nextVersion returns the version following current for a change, applying
+// the rules for major version zero.
+func nextVersion(current semver.Version, change Change) (semver.Version, error) {
+	if change == ChangeMajor && current.Major == 0 {
+		change = ChangeMinor
+	}
+	switch change {
+	case ChangeMajor:
+		return semver.Bump(current, semver.BumpMajor, "")
+	case ChangeMinor:
+		return semver.Bump(current, semver.BumpMinor, "")
+	case ChangePatch:
+		return semver.Bump(current, semver.BumpPatch, "")
+	}
+	current.Build = nil
+	return current, nil
+}
+
+// commitsSince returns the commits counted by the results d, i.e. those since
+// its tag, classified per Conventional Commits.
+func commitsSince(path string, d *semverdesc.DescribeResults, opts Options) ([]ConventionalCommit, error) {
+	if d.Distance == 0 {
+		return nil, nil
+	}
+	revRange := d.HashStr
+	if !(d.Approximate && d.TagName == opts.FallbackVersion) {
+		// a fallback version has no tag, so all commits available count
+		revRange = tagRef(opts.TagPrefix+d.TagName, opts) + ".." + d.HashStr
+	}
+	args := []string{"log", "--no-color", "-z", "--format=%H%n%B"}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, revRange, "--")
+	args = append(args, opts.Paths...)
+	output, err := gitCmd(path, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitMessages(output)
+}
+
+// parseCommitMessages parses the output of git log with -z and a format of
+// "%H%n%B", classifying each commit.
+func parseCommitMessages(output []byte) ([]ConventionalCommit, error) {
+	output = bytes.TrimSuffix(output, []byte{0})
+	if len(output) == 0 {
+		return nil, nil
+	}
+	records := bytes.Split(output, []byte{0})
+	commits := make([]ConventionalCommit, len(records))
+	for i, record := range records {
+		fields := strings.SplitN(string(record), "\n", 2)
+		if len(fields) != 2 {
+			return nil, errors.New("unable to match: [" + string(record) + "]")
+		}
+		commits[i] = parseConventionalCommit(fields[1])
+		commits[i].HashStr = fields[0]
+	}
+	return commits, nil
+}
+
+// regex to match the header of a conventional commit message, capturing the
+// type, scope and breaking change marker.
+var conventionalHeaderRegex = regexp.MustCompile(`^([A-Za-z][\w-]*)(?:\(([^()]*)\))?(!)?: \S`)
+
+// regex to match a breaking change footer of a conventional commit message.
+var breakingFooterRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// parseConventionalCommit classifies a commit message per Conventional
+// Commits, returning the commit without its HashStr set.
+func parseConventionalCommit(message string) ConventionalCommit {
+	message = strings.TrimSpace(message)
+	subject := message
+	if i := strings.IndexByte(message, '\n'); i != -1 {
+		subject = message[:i]
+	}
+	c := ConventionalCommit{Subject: subject}
+
+	match := conventionalHeaderRegex.FindStringSubmatch(subject)
+	if match == nil {
+		return c
+	}
+	c.Type, c.Scope = strings.ToLower(match[1]), match[2]
+	c.Breaking = match[3] == "!" || breakingFooterRegex.MatchString(message[len(subject):])
+	switch {
+	case c.Breaking:
+		c.Change = ChangeMajor
+	case c.Type == "feat":
+		c.Change = ChangeMinor
+	case c.Type == "fix":
+		c.Change = ChangePatch
+	}
+	return c
+}
@@ -0,0 +1,168 @@
+package describer
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+func Test_parseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    ConventionalCommit
+	}{
+		{
+			name:    "fix",
+			message: "fix: handle empty tags\n",
+			want:    ConventionalCommit{Subject: "fix: handle empty tags", Type: "fix", Change: ChangePatch},
+		},
+		{
+			name:    "feat with scope",
+			message: "feat(cli): add bump subcommand\n\nWith a body.\n",
+			want:    ConventionalCommit{Subject: "feat(cli): add bump subcommand", Type: "feat", Scope: "cli", Change: ChangeMinor},
+		},
+		{
+			name:    "breaking marker",
+			message: "refactor(api)!: drop DescribeLegacy\n",
+			want:    ConventionalCommit{Subject: "refactor(api)!: drop DescribeLegacy", Type: "refactor", Scope: "api", Breaking: true, Change: ChangeMajor},
+		},
+		{
+			name:    "breaking footer",
+			message: "feat: new config format\n\nBREAKING CHANGE: the old format is no longer read\n",
+			want:    ConventionalCommit{Subject: "feat: new config format", Type: "feat", Breaking: true, Change: ChangeMajor},
+		},
+		{
+			name:    "breaking hyphenated footer",
+			message: "fix: stricter parsing\n\nReviewed-by: someone\nBREAKING-CHANGE: invalid tags are rejected\n",
+			want:    ConventionalCommit{Subject: "fix: stricter parsing", Type: "fix", Breaking: true, Change: ChangeMajor},
+		},
+		{
+			name:    "breaking footer not in subject",
+			message: "docs: BREAKING CHANGE: explained\n",
+			want:    ConventionalCommit{Subject: "docs: BREAKING CHANGE: explained", Type: "docs"},
+		},
+		{
+			name:    "type is case insensitive",
+			message: "Feat: shout",
+			want:    ConventionalCommit{Subject: "Feat: shout", Type: "feat", Change: ChangeMinor},
+		},
+		{
+			name:    "other type",
+			message: "chore(deps): bump pflag",
+			want:    ConventionalCommit{Subject: "chore(deps): bump pflag", Type: "chore", Scope: "deps"},
+		},
+		{
+			name:    "not conventional",
+			message: "Merge branch 'main' into feature",
+			want:    ConventionalCommit{Subject: "Merge branch 'main' into feature"},
+		},
+		{
+			name:    "missing space",
+			message: "fix:typo",
+			want:    ConventionalCommit{Subject: "fix:typo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseConventionalCommit(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConventionalCommit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseCommitMessages(t *testing.T) {
+	output := []byte("56dc2041f2c45ab15d41e63058c1c44fff905e81\nfeat: one\n\nbody\n\x00" +
+		"71dd5072d51458a534ca7e0ec7c181d84754774d\nfix: two\n\x00")
+	want := []ConventionalCommit{
+		{HashStr: "56dc2041f2c45ab15d41e63058c1c44fff905e81", Subject: "feat: one", Type: "feat", Change: ChangeMinor},
+		{HashStr: "71dd5072d51458a534ca7e0ec7c181d84754774d", Subject: "fix: two", Type: "fix", Change: ChangePatch},
+	}
+	got, err := parseCommitMessages(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCommitMessages() = %+v, want %+v", got, want)
+	}
+
+	if got, err := parseCommitMessages(nil); err != nil || got != nil {
+		t.Errorf("parseCommitMessages(nil) = %v, %v, want nil", got, err)
+	}
+	if _, err := parseCommitMessages([]byte("56dc2041f2c45ab15d41e63058c1c44fff905e81\x00")); err == nil {
+		t.Error("parseCommitMessages() expected error for record without message")
+	}
+}
+
+func Test_nextVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		change  Change
+		want    string
+	}{
+		{name: "none", current: "v1.2.3", change: ChangeNone, want: "v1.2.3"},
+		{name: "patch", current: "v1.2.3", change: ChangePatch, want: "v1.2.4"},
+		{name: "minor", current: "v1.2.3", change: ChangeMinor, want: "v1.3.0"},
+		{name: "major", current: "v1.2.3", change: ChangeMajor, want: "v2.0.0"},
+		{name: "major version zero patch", current: "v0.2.3", change: ChangePatch, want: "v0.2.4"},
+		{name: "major version zero minor", current: "v0.2.3", change: ChangeMinor, want: "v0.3.0"},
+		{name: "major version zero breaking", current: "v0.2.3", change: ChangeMajor, want: "v0.3.0"},
+		{name: "releases prerelease", current: "v1.3.0-rc.1", change: ChangePatch, want: "v1.3.0"},
+		{name: "breaking after prerelease", current: "v1.3.0-rc.1", change: ChangeMajor, want: "v2.0.0"},
+		{name: "none drops build", current: "v1.2.3+meta", change: ChangeNone, want: "v1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := semver.Parse(tt.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := nextVersion(current, tt.change)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("nextVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextVersion_Drivers(t *testing.T) {
+	n := &NextVersion{
+		Change: ChangeMinor,
+		Commits: []ConventionalCommit{
+			{Subject: "feat: a", Change: ChangeMinor},
+			{Subject: "fix: b", Change: ChangePatch},
+			{Subject: "feat: c", Change: ChangeMinor},
+		},
+	}
+	want := []ConventionalCommit{n.Commits[0], n.Commits[2]}
+	if got := n.Drivers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Drivers() = %v, want %v", got, want)
+	}
+	n.Change = ChangeNone
+	if got := n.Drivers(); got != nil {
+		t.Errorf("Drivers() = %v, want nil", got)
+	}
+}
+
+func TestNext_semverOnly(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.0.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "feat: add widgets")
+	git(t, repo, "tag", "--annotate", "--message", "r", "latest")
+
+	n, err := Next(repo, "", Options{Candidates: DefaultCandidatesOption})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Next.String(); got != "v1.1.0" || n.Results.TagName != "v1.0.0" {
+		t.Errorf("Next() = %v from %v, want v1.1.0 from v1.0.0", got, n.Results.TagName)
+	}
+}
@@ -0,0 +1,57 @@
+package describer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+)
+
+// scopeToPaths adjusts the Distance and HashStr of d to only account for
+// commits which touch the Paths of opts, in the repository located at repo.
+func scopeToPaths(repo string, d *semverdesc.DescribeResults, opts Options) error {
+	tag := tagRef(d.TagName, opts)
+	args := []string{"rev-list"}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, tag+".."+d.HashStr, "--")
+	args = append(args, opts.Paths...)
+	output, err := gitCmd(repo, args...).Output()
+	if err != nil {
+		return err
+	}
+
+	hashes := parseRevList(output)
+	if len(hashes) == 0 {
+		// nothing has touched the paths since the tag, so describe the tagged
+		// commit itself.
+		hash, err := revParse(repo, tag+"^{commit}")
+		if err != nil {
+			return err
+		}
+		d.Distance, d.HashStr = 0, hash
+		return nil
+	}
+	// rev-list is in reverse chronological order, so the first is the latest
+	d.Distance, d.HashStr = uint(len(hashes)), hashes[0]
+	return nil
+}
+
+// tagRef returns the fully qualified ref for a tag name as output by git
+// describe for opts, avoiding any ambiguity with similarly named branches.
+func tagRef(name string, opts Options) string {
+	if all, _ := searchRefs(opts); all {
+		return "refs/" + name
+	}
+	return "refs/tags/" + name
+}
+
+// parseRevList parses the object names output by git rev-list.
+func parseRevList(output []byte) []string {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil
+	}
+	return strings.Split(string(output), "\n")
+}
@@ -0,0 +1,49 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tagRef(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		opts Options
+		want string
+	}{
+		{name: "tag", tag: "v1.2.3", opts: Options{}, want: "refs/tags/v1.2.3"},
+		{name: "all", tag: "tags/v1.2.3", opts: Options{All: true}, want: "refs/tags/v1.2.3"},
+		{name: "all branch", tag: "heads/main", opts: Options{All: true}, want: "refs/heads/main"},
+		{name: "all with prefix", tag: "services/billing/v1.4.2", opts: Options{All: true, TagPrefix: "services/billing/"}, want: "refs/tags/services/billing/v1.4.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagRef(tt.tag, tt.opts); got != tt.want {
+				t.Errorf("tagRef() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRevList(t *testing.T) {
+	tests := []struct {
+		name   string
+		output []byte
+		want   []string
+	}{
+		{name: "empty", output: []byte(""), want: nil},
+		{
+			name:   "multiple",
+			output: []byte("56dc2041f2c45ab15d41e63058c1c44fff905e81\n71dd5072d51458a534ca7e0ec7c181d84754774d\n"),
+			want:   []string{"56dc2041f2c45ab15d41e63058c1c44fff905e81", "71dd5072d51458a534ca7e0ec7c181d84754774d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRevList(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRevList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
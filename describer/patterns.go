@@ -0,0 +1,199 @@
+package describer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc/localgit"
+	"github.com/mroth/semverdesc/semver"
+)
+
+// searchRefs returns the effective All and Tags describe options for opts.
+func searchRefs(opts Options) (all, tags bool) {
+	if opts.All && (opts.SemverOnly || opts.TagPrefix != "") {
+		return false, true
+	}
+	return opts.All, opts.Tags
+}
+
+// searchPatterns returns the match and exclude patterns to pass to git
+// describe for opts.
+//
+// Patterns are relative to the TagPrefix, so it is prepended to each of them.
+//
+// git has no notion of SemVer, so for SemverOnly (without a MatchPattern of its
+// own) only tags which begin like a version are matched. If git chooses one
+// which turns out not to be SemVer, as caught by acceptsTag, it is rejected by
+// rejectAll from searching again, along with any others git could choose.
+func searchPatterns(opts Options) (match, exclude []string) {
+	prefix := globEscape(opts.TagPrefix)
+	switch {
+	case opts.MatchPattern != "":
+		match = append(match, prefix+opts.MatchPattern)
+	case opts.SemverOnly:
+		patterns := versionPatterns(opts)
+		if len(patterns) > 1 && !multipleMatchSupported() {
+			// older git only takes a single --match, so match broadly and
+			// leave it to acceptsTag alone
+			patterns = []string{"*"}
+		}
+		for _, p := range patterns {
+			match = append(match, prefix+p)
+		}
+	case opts.TagPrefix != "":
+		match = append(match, prefix+"*")
+	}
+	if opts.ExcludePattern != "" {
+		exclude = append(exclude, prefix+opts.ExcludePattern)
+	}
+	for _, t := range opts.rejected {
+		exclude = append(exclude, globEscape(t))
+	}
+	return match, exclude
+}
+
+// multipleMatchSupported reports whether the installed git accumulates
+// multiple --match patterns, assuming it does if the version can't be
+// determined so that any underlying problem is reported by git itself.
+var multipleMatchSupported = func() bool {
+	ok, err := localgit.HasVersion(localgit.VersionDescribeExclude)
+	return ok || err != nil
+}
+
+// versionPatterns returns glob(7) patterns matching at least all of the
+// versions acceptable for SemverOnly.
+func versionPatterns(opts Options) []string {
+	if mod := opts.goModule; mod != nil {
+		if mod.Major == 1 {
+			return []string{"v0.*", "v1.*"}
+		}
+		return []string{"v" + strconv.FormatUint(mod.Major, 10) + ".*"}
+	}
+	return []string{"[0-9]*", "v[0-9]*"}
+}
+
+// acceptsTag reports whether a tag name as output by git describe for opts is
+// acceptable, which is only in doubt for SemverOnly since git matched it by
+// glob alone.
+func acceptsTag(name string, opts Options) bool {
+	return !opts.SemverOnly || acceptsVersion(tagVersion(name, opts), opts)
+}
+
+// maxSearches is the number of times a search is repeated without the tags
+// which are not acceptable for SemverOnly, before giving up. Since rejectAll
+// rejects all of them at once, this is only a safeguard.
+const maxSearches = 10
+
+// errTooManySearches is returned when no acceptable tag is found within
+// maxSearches.
+var errTooManySearches = errors.New("too many tags which are not SemVer found, narrow the search with a match pattern")
+
+// reject returns opts with the tag names (as output by git describe) excluded
+// from the search.
+func (opts Options) reject(names ...string) Options {
+	rejected := make([]string, 0, len(opts.rejected)+len(names))
+	rejected = append(rejected, opts.rejected...)
+	for _, name := range names {
+		rejected = append(rejected, strings.TrimPrefix(name, "tags/"))
+	}
+	opts.rejected = rejected
+	return opts
+}
+
+// rejectAll returns opts with every tag selected by a git for-each-ref filter
+// (e.g. "--merged=<commit>") which git could match for opts, but which is not
+// acceptable for SemverOnly, rejected from the search. This way the search
+// need only be repeated once, rather than once for each such tag.
+func rejectAll(path, filter string, opts Options) (Options, error) {
+	output, err := gitCmd(path, "for-each-ref", "--format=%(refname)", filter, "refs/tags/").Output()
+	if err != nil {
+		return opts, err
+	}
+	match, exclude := searchPatterns(opts)
+	var rejected []string
+	for _, ref := range parseRevList(output) {
+		name := strings.TrimPrefix(ref, "refs/tags/")
+		if !acceptsTag(name, opts) && matchesGlobs(name, match, exclude) {
+			rejected = append(rejected, name)
+		}
+	}
+	return opts.reject(rejected...), nil
+}
+
+// matchesGlobs reports whether name matches any of the match patterns (or
+// there are none), and none of the exclude patterns, as git describe does.
+func matchesGlobs(name string, match, exclude []string) bool {
+	matched := len(match) == 0
+	for _, p := range match {
+		if wildmatch(p, name) {
+			matched = true
+			break
+		}
+	}
+	for _, p := range exclude {
+		if wildmatch(p, name) {
+			return false
+		}
+	}
+	return matched
+}
+
+// tagVersion returns the portion of a tag name as output by git describe for
+// opts which denotes the version, i.e. without any TagPrefix.
+func tagVersion(name string, opts Options) string {
+	// with --all, tags are named relative to refs/ instead
+	if all, _ := searchRefs(opts); all {
+		name = strings.TrimPrefix(name, "tags/")
+	}
+	return strings.TrimPrefix(name, opts.TagPrefix)
+}
+
+// matchesPatterns reports whether the tag name would be considered given the
+// search options of opts.
+//
+// Match and exclude patterns are applied with wildmatch, as git itself does.
+func matchesPatterns(name string, opts Options) bool {
+	if !strings.HasPrefix(name, opts.TagPrefix) {
+		return false
+	}
+	name = strings.TrimPrefix(name, opts.TagPrefix)
+	if opts.MatchPattern != "" {
+		if !wildmatch(opts.MatchPattern, name) {
+			return false
+		}
+	}
+	if opts.ExcludePattern != "" {
+		if wildmatch(opts.ExcludePattern, name) {
+			return false
+		}
+	}
+	if opts.SemverOnly && !acceptsVersion(name, opts) {
+		return false
+	}
+	return true
+}
+
+// acceptsVersion reports whether the version portion of a tag name is
+// acceptable for SemverOnly, which for a Go module also requires its major
+// version to match.
+func acceptsVersion(version string, opts Options) bool {
+	if opts.goModule != nil {
+		return opts.goModule.accepts(version)
+	}
+	return semver.IsValid(version)
+}
+
+// globEscape escapes the glob(7) special characters in s, so it can be used
+// as a pattern matching only itself.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
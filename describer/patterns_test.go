@@ -0,0 +1,195 @@
+package describer
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_globEscape(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "latest", want: "latest"},
+		{s: "deploy-prod-2026-10-01", want: "deploy-prod-2026-10-01"},
+		{s: "what?", want: `what\?`},
+		{s: "[weird]*tag", want: `\[weird\]\*tag`},
+		{s: `back\slash`, want: `back\\slash`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := globEscape(tt.s); got != tt.want {
+				t.Errorf("globEscape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchesPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		opts Options
+		want bool
+	}{
+		{name: "no patterns", tag: "latest", opts: Options{}, want: true},
+		{name: "match", tag: "v1.2.3", opts: Options{MatchPattern: "v*"}, want: true},
+		{name: "no match", tag: "latest", opts: Options{MatchPattern: "v*"}, want: false},
+		{name: "excluded", tag: "v1.2.3-rc.1", opts: Options{ExcludePattern: "*-rc*"}, want: false},
+		{name: "semver only", tag: "v1.2.3", opts: Options{SemverOnly: true}, want: true},
+		{name: "semver only excludes non-semver", tag: "deploy-prod-2026-10-01", opts: Options{SemverOnly: true}, want: false},
+		{name: "tag prefix", tag: "services/billing/v1.4.2", opts: Options{TagPrefix: "services/billing/"}, want: true},
+		{name: "outside tag prefix", tag: "services/auth/v1.4.2", opts: Options{TagPrefix: "services/billing/"}, want: false},
+		{name: "tag prefix relative match", tag: "services/billing/v1.4.2", opts: Options{TagPrefix: "services/billing/", MatchPattern: "v1.*"}, want: true},
+		{name: "match across slashes", tag: "services/billing/v1.4.2", opts: Options{MatchPattern: "services/*"}, want: true},
+		{name: "exclude across slashes", tag: "services/billing/v1.4.2", opts: Options{ExcludePattern: "*/v1.*"}, want: false},
+		{name: "tag prefix semver only", tag: "services/billing/v1.4.2", opts: Options{TagPrefix: "services/billing/", SemverOnly: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesPatterns(tt.tag, tt.opts); got != tt.want {
+				t.Errorf("matchesPatterns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_searchPatterns(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        Options
+		oldGit      bool
+		wantMatch   []string
+		wantExclude []string
+	}{
+		{name: "no patterns", opts: Options{}},
+		{
+			name:        "match and exclude",
+			opts:        Options{MatchPattern: "v*", ExcludePattern: "*-rc*"},
+			wantMatch:   []string{"v*"},
+			wantExclude: []string{"*-rc*"},
+		},
+		{
+			name:      "tag prefix",
+			opts:      Options{TagPrefix: "services/billing/"},
+			wantMatch: []string{"services/billing/*"},
+		},
+		{
+			name:      "semver only",
+			opts:      Options{SemverOnly: true},
+			wantMatch: []string{"[0-9]*", "v[0-9]*"},
+		},
+		{
+			name:      "semver only with tag prefix",
+			opts:      Options{SemverOnly: true, TagPrefix: "services/billing/"},
+			wantMatch: []string{"services/billing/[0-9]*", "services/billing/v[0-9]*"},
+		},
+		{
+			name:      "semver only on old git",
+			opts:      Options{SemverOnly: true, TagPrefix: "services/billing/"},
+			oldGit:    true,
+			wantMatch: []string{"services/billing/*"},
+		},
+		{
+			name:      "go module major version on old git",
+			opts:      Options{SemverOnly: true, goModule: &goModule{Major: 2}},
+			oldGit:    true,
+			wantMatch: []string{"v2.*"},
+		},
+		{
+			name:      "semver only with own match",
+			opts:      Options{SemverOnly: true, MatchPattern: "v1.*"},
+			wantMatch: []string{"v1.*"},
+		},
+		{
+			name:      "go module",
+			opts:      Options{SemverOnly: true, goModule: &goModule{Major: 1}},
+			wantMatch: []string{"v0.*", "v1.*"},
+		},
+		{
+			name:      "go module major version",
+			opts:      Options{SemverOnly: true, TagPrefix: "sub/", goModule: &goModule{Major: 2}},
+			wantMatch: []string{"sub/v2.*"},
+		},
+		{
+			name:        "rejected",
+			opts:        Options{SemverOnly: true, ExcludePattern: "*-rc*"}.reject("v1.2", "tags/v[3]"),
+			wantMatch:   []string{"[0-9]*", "v[0-9]*"},
+			wantExclude: []string{"*-rc*", "v1.2", `v\[3\]`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.oldGit {
+				defer func(f func() bool) { multipleMatchSupported = f }(multipleMatchSupported)
+				multipleMatchSupported = func() bool { return false }
+			}
+			match, exclude := searchPatterns(tt.opts)
+			if !reflect.DeepEqual(match, tt.wantMatch) {
+				t.Errorf("searchPatterns() match = %v, want %v", match, tt.wantMatch)
+			}
+			if !reflect.DeepEqual(exclude, tt.wantExclude) {
+				t.Errorf("searchPatterns() exclude = %v, want %v", exclude, tt.wantExclude)
+			}
+		})
+	}
+}
+
+func TestDescribe_semverOnly(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.0.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "two")
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.2")
+	git(t, repo, "tag", "--annotate", "--message", "r", "1.02.0")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "three")
+	git(t, repo, "tag", "--annotate", "--message", "r", "latest")
+	head := git(t, repo, "rev-parse", "HEAD")
+
+	opts := Options{SemverOnly: true, Candidates: DefaultCandidatesOption}
+	d, err := Describe(repo, "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.TagName != "v1.0.0" || d.Distance != 2 || d.HashStr != head {
+		t.Errorf("Describe() = %v+%v.%v, want v1.0.0+2.%v", d.TagName, d.Distance, d.HashStr, head)
+	}
+
+	many := DescribeMany(repo, []string{"HEAD", "HEAD~1"}, opts)
+	for i, want := range []uint{2, 1} {
+		if r := many[i]; r.Err != nil || r.Results.TagName != "v1.0.0" || r.Results.Distance != want {
+			t.Errorf("DescribeMany()[%d] = %+v, want v1.0.0 at distance %v", i, r, want)
+		}
+	}
+
+	git(t, repo, "tag", "--annotate", "--message", "r", "v2.0.0")
+	c, err := DescribeContains(repo, "HEAD~1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TagName != "v2.0.0" {
+		t.Errorf("DescribeContains() = %v, want v2.0.0", c.TagName)
+	}
+}
+
+func TestDescribe_semverOnlyManyRejected(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.0.0")
+	// more tags which only look like versions than searches allow rejecting
+	// one at a time
+	for i := 1; i <= 3*maxSearches; i++ {
+		git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "nightly")
+		git(t, repo, "tag", "--annotate", "--message", "r", "v2026-10-"+strconv.Itoa(i))
+	}
+
+	d, err := Describe(repo, "", Options{SemverOnly: true, Candidates: DefaultCandidatesOption})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint(3 * maxSearches); d.TagName != "v1.0.0" || d.Distance != want {
+		t.Errorf("Describe() = %v+%v, want v1.0.0+%v", d.TagName, d.Distance, want)
+	}
+}
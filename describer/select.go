@@ -0,0 +1,233 @@
+package describer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+	"github.com/mroth/semverdesc/semver"
+)
+
+// SelectionPolicy determines how a tag is chosen when there are multiple
+// candidates to describe a commit with, e.g. several tags on the same commit,
+// or several tags at the same distance.
+type SelectionPolicy int
+
+// Available selection policies.
+const (
+	// SelectGit uses whichever tag git itself chooses, which for tags on the
+	// same commit or at the same distance is based on tag date.
+	SelectGit SelectionPolicy = iota
+	// SelectHighestPrecedence only considers tags which parse as SemVer
+	// (optionally prefixed with a "v"), as SemverOnly does, and chooses the
+	// one with the highest SemVer precedence among the nearest of them. Tags
+	// which are not SemVer are never chosen, however near.
+	SelectHighestPrecedence
+)
+
+var selectionPolicyNames = map[SelectionPolicy]string{
+	SelectGit:               "git",
+	SelectHighestPrecedence: "semver",
+}
+
+// String returns the name of the policy, as accepted by ParseSelectionPolicy.
+func (p SelectionPolicy) String() string {
+	if name, ok := selectionPolicyNames[p]; ok {
+		return name
+	}
+	return "SelectionPolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the policy by name.
+func (p SelectionPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the names
+// accepted by ParseSelectionPolicy.
+func (p *SelectionPolicy) UnmarshalText(text []byte) error {
+	policy, err := ParseSelectionPolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+// ParseSelectionPolicy returns the SelectionPolicy with the given name, either
+// "git" or "semver".
+func ParseSelectionPolicy(name string) (SelectionPolicy, error) {
+	for p, n := range selectionPolicyNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return SelectGit, errors.New("unknown selection policy: " + name)
+}
+
+// describeHighestPrecedence performs a describe using the
+// SelectHighestPrecedence policy.
+func describeHighestPrecedence(path, commitish string, opts Options) (*semverdesc.DescribeResults, error) {
+	e, err := explain(path, commitish, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.Results, nil
+}
+
+// selectHighestPrecedence chooses the SemVer tag with the highest precedence
+// among the candidates at the same distance as the results, returning it along
+// with the names of the other such SemVer candidates. Candidates which are not
+// SemVer are dropped beforehand, so the results must be of a SemVer tag for
+// them to be the nearest, as explain ensures by searching with SemverOnly.
+func selectHighestPrecedence(d *semverdesc.DescribeResults, candidates []Candidate, opts Options) (string, []string) {
+	var (
+		best       string
+		bestVer    semver.Version
+		considered []string
+	)
+	for _, c := range candidates {
+		if c.Depth != d.Distance {
+			continue
+		}
+		v, err := semver.Parse(tagVersion(c.TagName, opts))
+		if err != nil {
+			continue
+		}
+		considered = append(considered, c.TagName)
+		if best == "" || semver.Compare(v, bestVer) > 0 {
+			best, bestVer = c.TagName, v
+		}
+	}
+	if best == "" {
+		best = d.TagName
+	}
+
+	var alternatives []string
+	for _, name := range considered {
+		if name != best {
+			alternatives = append(alternatives, name)
+		}
+	}
+	return best, alternatives
+}
+
+// runDebug runs a git describe command with debug output enabled, returning
+// its standard output and debug output separately. If the command fails, the
+// debug output is available on the exec.ExitError as Stderr, as it would be
+// from exec.Cmd.Output.
+func runDebug(cmd *exec.Cmd) (output, debug []byte, err error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// the debug output is localized, so make sure we can parse it
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	output, err = cmd.Output()
+	if exiterr, ok := err.(*exec.ExitError); ok {
+		exiterr.Stderr = stderr.Bytes()
+	}
+	return output, stderr.Bytes(), err
+}
+
+// regex to match a candidate line in git describe debug output, e.g.
+// " annotated          2 v1.0.0"
+var debugCandidateRegex = regexp.MustCompile(`^ (\S+)\s+(\d+) (.+)$`)
+
+// parseDebugCandidates parses the candidate tags from git describe --debug
+// output.
+func parseDebugCandidates(debug []byte) ([]Candidate, error) {
+	var candidates []Candidate
+	scanner := bufio.NewScanner(bytes.NewReader(debug))
+	for scanner.Scan() {
+		match := debugCandidateRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		depth, err := strconv.Atoi(match[2])
+		if err != nil {
+			return nil, errors.New("could not parse depth: " + match[2])
+		}
+		candidates = append(candidates, Candidate{
+			TagName: match[3],
+			Type:    match[1],
+			Depth:   uint(depth),
+		})
+	}
+	return candidates, scanner.Err()
+}
+
+// addSiblingCandidates returns the candidates along with any other tags
+// pointing at the same commit as those at depth, which git omits from its
+// candidates as it only considers one tag per commit.
+func addSiblingCandidates(repo string, candidates []Candidate, depth uint, opts Options) ([]Candidate, error) {
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		seen[c.TagName] = true
+	}
+	var all []Candidate
+	for _, c := range candidates {
+		all = append(all, c)
+		if c.Depth != depth {
+			continue
+		}
+		hash, err := revParse(repo, tagRef(c.TagName, opts)+"^{commit}")
+		if err != nil {
+			return nil, err
+		}
+		siblings, err := pointsAt(repo, hash, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, sibling := range siblings {
+			if !seen[sibling.TagName] {
+				seen[sibling.TagName] = true
+				sibling.Depth = c.Depth
+				all = append(all, sibling)
+			}
+		}
+	}
+	return all, nil
+}
+
+// pointsAt returns the tags which point directly at the commit hash as
+// candidates, filtered in the same way git describe would for opts.
+func pointsAt(repo, hash string, opts Options) ([]Candidate, error) {
+	output, err := gitCmd(repo, "for-each-ref", "--points-at="+hash,
+		"--format=%(objecttype) %(refname)", "refs/tags/").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		c := Candidate{
+			TagName: strings.TrimPrefix(fields[1], "refs/tags/"),
+			Type:    "lightweight",
+		}
+		if fields[0] == "tag" {
+			c.Type = "annotated"
+		}
+		all, tags := searchRefs(opts)
+		if c.Type != "annotated" && !tags && !all {
+			continue
+		}
+		if all {
+			c.TagName = "tags/" + c.TagName
+		}
+		if !matchesPatterns(strings.TrimPrefix(c.TagName, "tags/"), opts) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, scanner.Err()
+}
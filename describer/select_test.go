@@ -0,0 +1,153 @@
+package describer
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mroth/semverdesc"
+)
+
+func Test_parseDebugCandidates(t *testing.T) {
+	debug := []byte(`describe HEAD
+No exact match on refs or tags, searching to describe
+ annotated          2 v1.2.0
+ annotated          2 v1.2.0-rc.3
+ lightweight        2 latest
+ annotated          5 v1.1.0
+traversed 8 commits
+`)
+	want := []Candidate{
+		{TagName: "v1.2.0", Type: "annotated", Depth: 2},
+		{TagName: "v1.2.0-rc.3", Type: "annotated", Depth: 2},
+		{TagName: "latest", Type: "lightweight", Depth: 2},
+		{TagName: "v1.1.0", Type: "annotated", Depth: 5},
+	}
+	got, err := parseDebugCandidates(debug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDebugCandidates() = %v, want %v", got, want)
+	}
+}
+
+func Test_selectHighestPrecedence(t *testing.T) {
+	tests := []struct {
+		name             string
+		results          semverdesc.DescribeResults
+		candidates       []Candidate
+		opts             Options
+		wantTag          string
+		wantAlternatives []string
+	}{
+		{
+			name:    "release preferred over prerelease and non-semver",
+			results: semverdesc.DescribeResults{TagName: "v1.2.0-rc.3", Distance: 2},
+			candidates: []Candidate{
+				{TagName: "v1.2.0-rc.3", Depth: 2},
+				{TagName: "latest", Depth: 2},
+				{TagName: "v1.2.0", Depth: 2},
+				{TagName: "v1.1.0", Depth: 5},
+			},
+			wantTag:          "v1.2.0",
+			wantAlternatives: []string{"v1.2.0-rc.3"},
+		},
+		{
+			name:    "non-semver candidates dropped",
+			results: semverdesc.DescribeResults{TagName: "v1.0.0", Distance: 0},
+			candidates: []Candidate{
+				{TagName: "nightly", Depth: 0},
+				{TagName: "v1.0.0", Depth: 0},
+				{TagName: "stable", Depth: 0},
+			},
+			wantTag:          "v1.0.0",
+			wantAlternatives: nil,
+		},
+		{
+			name:    "tags relative to refs with --all",
+			results: semverdesc.DescribeResults{TagName: "tags/v1.0.0", Distance: 1},
+			opts:    Options{All: true},
+			candidates: []Candidate{
+				{TagName: "tags/v1.0.0", Depth: 1},
+				{TagName: "tags/v1.1.0", Depth: 1},
+			},
+			wantTag:          "tags/v1.1.0",
+			wantAlternatives: []string{"tags/v1.0.0"},
+		},
+		{
+			name:    "tag prefix",
+			results: semverdesc.DescribeResults{TagName: "services/billing/v1.4.2-rc.1", Distance: 3},
+			candidates: []Candidate{
+				{TagName: "services/billing/v1.4.2-rc.1", Depth: 3},
+				{TagName: "services/billing/v1.4.2", Depth: 3},
+			},
+			opts:             Options{TagPrefix: "services/billing/"},
+			wantTag:          "services/billing/v1.4.2",
+			wantAlternatives: []string{"services/billing/v1.4.2-rc.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, alternatives := selectHighestPrecedence(&tt.results, tt.candidates, tt.opts)
+			if tag != tt.wantTag {
+				t.Errorf("selectHighestPrecedence() tag = %v, want %v", tag, tt.wantTag)
+			}
+			if !reflect.DeepEqual(alternatives, tt.wantAlternatives) {
+				t.Errorf("selectHighestPrecedence() alternatives = %v, want %v", alternatives, tt.wantAlternatives)
+			}
+		})
+	}
+}
+
+func TestSelectionPolicy_jsonRoundTrip(t *testing.T) {
+	want := Options{Selection: SelectHighestPrecedence, Shallow: ShallowFallback}
+	encoded, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"selection":"semver"`) ||
+		!strings.Contains(string(encoded), `"shallow":"fallback"`) {
+		t.Errorf("json.Marshal() = %s, want policies encoded by name", encoded)
+	}
+	var got Options
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Selection != want.Selection || got.Shallow != want.Shallow {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDescribe_selectHighestPrecedence(t *testing.T) {
+	repo := gitRepo(t)
+	defer os.RemoveAll(repo)
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.2.0")
+	git(t, repo, "tag", "--annotate", "--message", "r", "v1.2.0-rc.3")
+	git(t, repo, "commit", "--quiet", "--allow-empty", "--message", "two")
+	git(t, repo, "tag", "--annotate", "--message", "r", "nightly")
+
+	opts := Options{Selection: SelectHighestPrecedence, Candidates: DefaultCandidatesOption}
+	d, err := Describe(repo, "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.TagName != "v1.2.0" || d.Distance != 1 {
+		t.Errorf("Describe() = %v+%v, want v1.2.0+1", d.TagName, d.Distance)
+	}
+	if want := []string{"v1.2.0-rc.3"}; !reflect.DeepEqual(d.Alternatives, want) {
+		t.Errorf("Describe() alternatives = %v, want %v", d.Alternatives, want)
+	}
+
+	e, err := Explain(repo, "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range e.Candidates {
+		if c.TagName == "nightly" {
+			t.Errorf("Explain() candidates = %v, want no nightly", e.Candidates)
+		}
+	}
+}
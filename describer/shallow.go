@@ -0,0 +1,157 @@
+package describer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+)
+
+// ShallowPolicy determines how a shallow repository, whose history (and thus
+// tags) may be incomplete, is described.
+type ShallowPolicy int
+
+// Available shallow policies.
+const (
+	// ShallowApproximate describes a shallow repository as usual, but marks
+	// the results as Approximate since the distance may be wrong. If it can
+	// not be described at all, a *ShallowRepositoryError is returned.
+	ShallowApproximate ShallowPolicy = iota
+	// ShallowError returns a *ShallowRepositoryError for any shallow
+	// repository.
+	ShallowError
+	// ShallowFallback describes a shallow repository as ShallowApproximate
+	// does, but if it can not be described at all uses the FallbackVersion
+	// as the tag instead, with the distance being the number of commits
+	// available. The results are always marked as Approximate.
+	ShallowFallback
+)
+
+var shallowPolicyNames = map[ShallowPolicy]string{
+	ShallowApproximate: "approximate",
+	ShallowError:       "error",
+	ShallowFallback:    "fallback",
+}
+
+// String returns the name of the policy, as accepted by ParseShallowPolicy.
+func (p ShallowPolicy) String() string {
+	if name, ok := shallowPolicyNames[p]; ok {
+		return name
+	}
+	return "ShallowPolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the policy by name.
+func (p ShallowPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the names
+// accepted by ParseShallowPolicy.
+func (p *ShallowPolicy) UnmarshalText(text []byte) error {
+	policy, err := ParseShallowPolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+// ParseShallowPolicy returns the ShallowPolicy with the given name, either
+// "approximate", "error" or "fallback".
+func ParseShallowPolicy(name string) (ShallowPolicy, error) {
+	for p, n := range shallowPolicyNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return ShallowApproximate, errors.New("unknown shallow policy: " + name)
+}
+
+// ShallowRepositoryError is returned when describing a shallow repository is
+// refused by the ShallowPolicy, or fails as the history is incomplete.
+type ShallowRepositoryError struct {
+	// The error from the underlying describe, if it failed
+	Err error
+}
+
+func (e *ShallowRepositoryError) Error() string {
+	msg := "repository is shallow, so tags may be missing or the distance wrong" +
+		" (fetch with --unshallow and --tags to fix)"
+	if e.Err == nil {
+		return msg
+	}
+	var reason string
+	if exiterr, ok := e.Err.(*exec.ExitError); ok && len(exiterr.Stderr) > 0 {
+		reason = strings.TrimSpace(string(exiterr.Stderr))
+	} else {
+		reason = e.Err.Error()
+	}
+	return msg + ": " + reason
+}
+
+// isShallow reports whether the repository located at path is a shallow
+// clone, which is the case when it has a shallow file.
+func isShallow(path string) (bool, error) {
+	var shallowFile string
+	if _, commonDir, err := findGitDirs(path); err == nil {
+		shallowFile = filepath.Join(commonDir, "shallow")
+	} else {
+		output, err := gitCmd(path, "rev-parse", "--git-path", "shallow").Output()
+		if err != nil {
+			return false, err
+		}
+		shallowFile = string(bytes.TrimSpace(output))
+		if !filepath.IsAbs(shallowFile) {
+			shallowFile = filepath.Join(path, shallowFile)
+		}
+	}
+
+	_, err := os.Stat(shallowFile)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// describeShallowFallback returns the results for describing commitish in a
+// shallow repository which failed with err, according to the ShallowPolicy.
+func describeShallowFallback(path, commitish string, err error, opts Options) (*semverdesc.DescribeResults, error) {
+	if _, ok := err.(*exec.ExitError); !ok {
+		return nil, err
+	}
+	if opts.Shallow != ShallowFallback || opts.FallbackVersion == "" {
+		return nil, &ShallowRepositoryError{Err: err}
+	}
+
+	rev := commitish
+	if rev == "" {
+		rev = "HEAD"
+	}
+	hash, err := revParse(path, rev+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+	distance, err := revCount(path, hash)
+	if err != nil {
+		return nil, err
+	}
+	d := &semverdesc.DescribeResults{
+		TagName:     opts.FallbackVersion,
+		Distance:    distance,
+		HashStr:     hash,
+		Approximate: true,
+	}
+	// the failed describe never got to check the working tree
+	if commitish == "" {
+		if d.Dirty, d.Broken, err = statusDirty(path, opts); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
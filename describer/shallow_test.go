@@ -0,0 +1,33 @@
+package describer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseShallowPolicy(t *testing.T) {
+	for _, p := range []ShallowPolicy{ShallowApproximate, ShallowError, ShallowFallback} {
+		got, err := ParseShallowPolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseShallowPolicy(%q) = %v, %v, want %v", p.String(), got, err, p)
+		}
+	}
+	if _, err := ParseShallowPolicy("deep"); err == nil {
+		t.Error("ParseShallowPolicy(\"deep\") expected error")
+	}
+}
+
+func Test_isShallow(t *testing.T) {
+	repo := fakeRepo(t)
+	defer os.RemoveAll(repo)
+
+	if got, err := isShallow(repo); err != nil || got {
+		t.Errorf("isShallow() = %v, %v, want false", got, err)
+	}
+	writeFile(t, filepath.Join(repo, ".git", "shallow"),
+		"56dc2041f2c45ab15d41e63058c1c44fff905e81\n")
+	if got, err := isShallow(repo); err != nil || !got {
+		t.Errorf("isShallow() = %v, %v, want true", got, err)
+	}
+}
@@ -0,0 +1,109 @@
+package describer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/mroth/semverdesc"
+)
+
+// SubmoduleReport is the result of describing a superproject along with all
+// of its submodules.
+type SubmoduleReport struct {
+	// The results of describing the superproject
+	Superproject *semverdesc.DescribeResults
+	// The results of describing each submodule, in the order git lists them
+	Submodules []SubmoduleResults
+}
+
+// SubmoduleResults are the results of describing a single submodule.
+type SubmoduleResults struct {
+	// The path of the submodule, relative to the superproject
+	Path string
+	// Modified is true if the submodule is checked out at a commit other than
+	// the one recorded in the superproject.
+	Modified bool
+	// The results of describing the submodule, nil if Err is set
+	Results *semverdesc.DescribeResults
+	// Any error encountered while describing the submodule
+	Err error
+}
+
+// DescribeSubmodules describes the working tree of the git repository located
+// at path, along with every initialized submodule (recursively) using the same
+// options. Uninitialized submodules are skipped.
+//
+// Errors describing a submodule (e.g. as it has no tags) are set on its
+// SubmoduleResults, whereas errors describing the superproject or listing its
+// submodules are returned, and may be of type exec.ExitError.
+func DescribeSubmodules(path string, opts Options) (*SubmoduleReport, error) {
+	super, err := Describe(path, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	output, err := gitCmd(path, "submodule", "status", "--recursive").Output()
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := parseSubmoduleStatus(output)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SubmoduleReport{Superproject: super}
+	for _, s := range statuses {
+		if s.uninitialized {
+			continue
+		}
+		d, err := Describe(filepath.Join(path, s.path), "", opts)
+		report.Submodules = append(report.Submodules, SubmoduleResults{
+			Path:     s.path,
+			Modified: s.modified,
+			Results:  d,
+			Err:      err,
+		})
+		if s.modified && opts.DirtySubmodules {
+			super.Dirty = true
+		}
+	}
+	return report, nil
+}
+
+// submoduleStatus is a single line of `git submodule status` output.
+type submoduleStatus struct {
+	path          string
+	uninitialized bool
+	modified      bool
+}
+
+// parseSubmoduleStatus parses the output of `git submodule status`, where
+// each line is in the form "<state><sha1> <path>[ (<describe>)]".
+func parseSubmoduleStatus(output []byte) ([]submoduleStatus, error) {
+	var statuses []submoduleStatus
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line[1:], " ", 2)
+		if len(fields) != 2 {
+			return nil, errors.New("unable to match: [" + line + "]")
+		}
+		path := fields[1]
+		if strings.HasSuffix(path, ")") {
+			if i := strings.LastIndex(path, " ("); i != -1 {
+				path = path[:i]
+			}
+		}
+		statuses = append(statuses, submoduleStatus{
+			path:          path,
+			uninitialized: line[0] == '-',
+			modified:      line[0] == '+',
+		})
+	}
+	return statuses, scanner.Err()
+}
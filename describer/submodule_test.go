@@ -0,0 +1,49 @@
+package describer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseSubmoduleStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  []byte
+		want    []submoduleStatus
+		wantErr bool
+	}{
+		{
+			name: "typical states",
+			output: []byte(" 56c853912c63bae96f3957dd991680bc77e3fbbb libs/core (v0.3.1)\n" +
+				"+ea2f6b88b89f42c007eedb74cd9a6fbf5ef34871 libs/my lib (v0.3.1-1-gea2f6b8)\n" +
+				"-71dd5072d51458a534ca7e0ec7c181d84754774d vendor/unused\n"),
+			want: []submoduleStatus{
+				{path: "libs/core"},
+				{path: "libs/my lib", modified: true},
+				{path: "vendor/unused", uninitialized: true},
+			},
+		},
+		{
+			name:   "no submodules",
+			output: []byte(""),
+			want:   nil,
+		},
+		{
+			name:    "unexpected format",
+			output:  []byte(" 56c853912c63bae96f3957dd991680bc77e3fbbb\n"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSubmoduleStatus(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSubmoduleStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSubmoduleStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
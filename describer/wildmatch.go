@@ -0,0 +1,118 @@
+package describer
+
+import "strings"
+
+// wildmatch reports whether name matches the glob(7) pattern as git's own
+// wildmatch does for the patterns of git describe --match and --exclude, in
+// which (unlike path.Match) a "*" also matches "/", e.g. "services/*" matches
+// "services/billing/v1.4.2". A malformed pattern matches nothing.
+func wildmatch(pattern, name string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if wildmatch(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if name == "" {
+				return false
+			}
+		case '[':
+			if name == "" {
+				return false
+			}
+			rest, matched, ok := matchClass(pattern[1:], name[0])
+			if !ok || !matched {
+				return false
+			}
+			pattern, name = rest, name[1:]
+			continue
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if name == "" || name[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return name == ""
+}
+
+// matchClass matches c against the bracket expression at the start of
+// pattern, following its opening "[", returning the rest of the pattern after
+// it and whether c matched. If the bracket expression is malformed, ok is
+// false.
+func matchClass(pattern string, c byte) (rest string, matched, ok bool) {
+	negate := false
+	if pattern != "" && (pattern[0] == '!' || pattern[0] == '^') {
+		negate, pattern = true, pattern[1:]
+	}
+	for first := true; ; first = false {
+		if pattern == "" {
+			return "", false, false
+		}
+		ch := pattern[0]
+		switch {
+		case ch == ']' && !first:
+			return pattern[1:], matched != negate, true
+		case strings.HasPrefix(pattern, "[:"):
+			end := strings.Index(pattern[2:], ":]")
+			if end == -1 {
+				return "", false, false
+			}
+			is, known := charClasses[pattern[2:2+end]]
+			if !known {
+				return "", false, false
+			}
+			matched = matched || is(c)
+			pattern = pattern[2+end+2:]
+			continue
+		case ch == '\\' && len(pattern) > 1:
+			pattern = pattern[1:]
+			ch = pattern[0]
+		}
+		pattern = pattern[1:]
+		if len(pattern) > 1 && pattern[0] == '-' && pattern[1] != ']' {
+			hi := pattern[1]
+			pattern = pattern[2:]
+			if hi == '\\' && pattern != "" {
+				hi, pattern = pattern[0], pattern[1:]
+			}
+			matched = matched || (ch <= c && c <= hi)
+			continue
+		}
+		matched = matched || ch == c
+	}
+}
+
+// charClasses are the character classes of bracket expressions, e.g.
+// "[[:digit:]]", supported by git's wildmatch.
+var charClasses = map[string]func(c byte) bool{
+	"alnum":  func(c byte) bool { return isAlpha(c) || isDigit(c) },
+	"alpha":  isAlpha,
+	"blank":  func(c byte) bool { return c == ' ' || c == '\t' },
+	"cntrl":  func(c byte) bool { return c < ' ' || c == 0x7f },
+	"digit":  isDigit,
+	"graph":  func(c byte) bool { return c > ' ' && c < 0x7f },
+	"lower":  func(c byte) bool { return 'a' <= c && c <= 'z' },
+	"print":  func(c byte) bool { return c >= ' ' && c < 0x7f },
+	"punct":  func(c byte) bool { return c > ' ' && c < 0x7f && !isAlpha(c) && !isDigit(c) },
+	"space":  func(c byte) bool { return c == ' ' || ('\t' <= c && c <= '\r') },
+	"upper":  func(c byte) bool { return 'A' <= c && c <= 'Z' },
+	"xdigit": func(c byte) bool { return isDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F') },
+}
+
+func isAlpha(c byte) bool { return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
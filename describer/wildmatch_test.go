@@ -0,0 +1,44 @@
+package describer
+
+import "testing"
+
+func Test_wildmatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"v1.2.3", "v1.2.3", true},
+		{"v1.2.3", "v1.2.4", false},
+		{"v*", "v1.2.3", true},
+		{"v*", "latest", false},
+		{"*", "", true},
+		{"services/*", "services/billing/v1.4.2", true},
+		{"*/v1.*", "services/billing/v1.4.2", true},
+		{"services/**/v*", "services/billing/v1.4.2", true},
+		{"v?.0.0", "v1.0.0", true},
+		{"v?.0.0", "v10.0.0", false},
+		{"*-rc*", "v1.2.3-rc.1", true},
+		{"*-rc*", "v1.2.3", false},
+		{"v[0-9]*", "v1.2.3", true},
+		{"v[0-9]*", "vx", false},
+		{"v[!0-9]*", "vx", true},
+		{"v[^0-9]*", "v1", false},
+		{"[]]", "]", true},
+		{"[!]]", "a", true},
+		{"v[[:digit:]].*", "v1.0", true},
+		{"v[[:digit:]].*", "va.0", false},
+		{"[[:alpha:][:digit:]]", "z", true},
+		{"[[:nope:]]", "a", false},
+		{`v1\*`, "v1*", true},
+		{`v1\*`, "v12", false},
+		{`[\]]`, "]", true},
+		{"[a-", "a", false},
+		{"v1", "v12", false},
+	}
+	for _, tt := range tests {
+		if got := wildmatch(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("wildmatch(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,14 +1,9 @@
-// Package localgit provides helpers for constructing command line flags for
-// interacting with the git cli tool.
+// Package localgit provides helpers for constructing and parsing command line
+// flags for interacting with the git cli tool.
 package localgit
 
 import "fmt"
 
-/*
-TODO: git describe actually supports multiple invocations of --match/--exclude,
-build in support here even if not using just to cover all possible bases.
-*/
-
 // DescribeOptions are possible flags for modifying a `git describe` operation.
 type DescribeOptions struct {
 	// Describe the state of the working tree. When the working tree matches
@@ -75,6 +70,10 @@ type DescribeOptions struct {
 	// the list of patterns.
 	MatchPattern string
 
+	// Additional patterns to match along with MatchPattern, as if --match
+	// were given multiple times.
+	MatchPatterns []string
+
 	// Do not consider tags matching the given glob(7) pattern, excluding the
 	// "refs/tags/" prefix. If used with --all, it also does not consider
 	// local branches and remote-tracking references matching the pattern,
@@ -87,6 +86,10 @@ type DescribeOptions struct {
 	// reset the list of patterns.
 	ExcludePattern string
 
+	// Additional patterns to exclude along with ExcludePattern, as if
+	// --exclude were given multiple times.
+	ExcludePatterns []string
+
 	// Show uniquely abbreviated commit object as fallback.
 	Always bool
 
@@ -153,13 +156,36 @@ func (o *DescribeOptions) Flags() []string {
 	args = appendToggleFlag(args, "--exact-match", o.ExactMatch)
 	args = appendToggleFlag(args, "--debug", o.Debug)
 	args = appendToggleFlag(args, "--long", o.Long)
-	args = appendValueFlag(args, "--match", o.MatchPattern)
-	args = appendValueFlag(args, "--exclude", o.ExcludePattern)
+	args = appendValueFlags(args, "--match", o.matchPatterns())
+	args = appendValueFlags(args, "--exclude", o.excludePatterns())
 	args = appendToggleFlag(args, "--always", o.Always)
 	args = appendToggleFlag(args, "--first-parent", o.FirstParent)
 	return args
 }
 
+// matchPatterns returns all of the patterns to match, MatchPattern first.
+func (o *DescribeOptions) matchPatterns() []string {
+	return joinPatterns(o.MatchPattern, o.MatchPatterns)
+}
+
+// excludePatterns returns all of the patterns to exclude, ExcludePattern
+// first.
+func (o *DescribeOptions) excludePatterns() []string {
+	return joinPatterns(o.ExcludePattern, o.ExcludePatterns)
+}
+
+// joinPatterns returns the non-empty patterns of a single pattern field and
+// its accompanying list.
+func joinPatterns(pattern string, patterns []string) []string {
+	var joined []string
+	for _, p := range append([]string{pattern}, patterns...) {
+		if p != "" {
+			joined = append(joined, p)
+		}
+	}
+	return joined
+}
+
 // append key=value flag IF not same as default, this appears to only apply to
 // uint values for git-describe, so hard-coded that way for now instead of any
 // Stringer.
@@ -178,6 +204,14 @@ func appendValueFlag(args []string, flag string, v string) []string {
 	return args
 }
 
+// append key=value flag for each of the values, IF non-empty
+func appendValueFlags(args []string, flag string, vs []string) []string {
+	for _, v := range vs {
+		args = appendValueFlag(args, flag, v)
+	}
+	return args
+}
+
 // append toggle flag (boolean that defaults to false), IF non-zero
 func appendToggleFlag(args []string, flag string, on bool) []string {
 	if on {
@@ -50,6 +50,15 @@ func TestDescribeOptions_Flags(t *testing.T) {
 			}),
 			want: []string{"--dirty=-filthy"},
 		},
+		{
+			name: "repeated value flag",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.MatchPattern = "v*"
+				o.MatchPatterns = []string{"release-*"}
+				o.ExcludePattern = "*-rc*"
+			}),
+			want: []string{"--match=v*", "--match=release-*", "--exclude=*-rc*"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,219 @@
+package localgit
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// argKind is whether a git-describe flag takes a value.
+type argKind int
+
+const (
+	noArg       argKind = iota // e.g. --tags
+	optionalArg                // e.g. --dirty[=<mark>]
+	requiredArg                // e.g. --match <pattern>
+)
+
+// describeFlagArgs are the flags understood by git-describe (without their
+// leading "--"), along with whether they take a value.
+var describeFlagArgs = map[string]argKind{
+	"dirty":        optionalArg,
+	"broken":       optionalArg,
+	"all":          noArg,
+	"tags":         noArg,
+	"contains":     noArg,
+	"abbrev":       optionalArg,
+	"candidates":   requiredArg,
+	"exact-match":  noArg,
+	"debug":        noArg,
+	"long":         noArg,
+	"match":        requiredArg,
+	"exclude":      requiredArg,
+	"always":       noArg,
+	"first-parent": noArg,
+}
+
+// ParseFlags parses git-describe command line arguments into DescribeOptions,
+// returning them along with the remaining arguments (the commit-ishes), in
+// order. It is the inverse of Flags, starting from NewDescribeOptions.
+//
+// All the forms accepted by git-describe itself are understood: values given
+// as either "--flag=value" or "--flag value" for flags requiring a value,
+// "--dirty" and "--broken" with or without a mark, negation with "--no-flag"
+// (which clears accumulated --match and --exclude patterns), unambiguous
+// abbreviations of flag names, options interleaved with commit-ishes, and "--"
+// to end the options. Additionally "--abbrev <n>" is accepted when n is a
+// number, although git itself would treat it as a commit-ish.
+func ParseFlags(args []string) (*DescribeOptions, []string, error) {
+	o := NewDescribeOptions()
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !strings.HasPrefix(arg, "--") {
+			return nil, nil, errors.New("unknown switch: " + arg)
+		}
+
+		name, value := arg[2:], ""
+		hasValue := false
+		if eq := strings.Index(name, "="); eq != -1 {
+			name, value, hasValue = name[:eq], name[eq+1:], true
+		}
+		negated := false
+		flag, err := resolveFlag(name)
+		if err != nil && strings.HasPrefix(name, "no-") {
+			flag, err = resolveFlag(strings.TrimPrefix(name, "no-"))
+			negated = true
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch kind := describeFlagArgs[flag]; {
+		case hasValue && (negated || kind == noArg):
+			return nil, nil, errors.New("option does not take a value: --" + name)
+		case negated:
+		case kind == requiredArg && !hasValue:
+			if i+1 >= len(args) {
+				return nil, nil, errors.New("option requires a value: --" + flag)
+			}
+			i++
+			value, hasValue = args[i], true
+		case flag == "abbrev" && !hasValue && i+1 < len(args) && isNumber(args[i+1]):
+			i++
+			value, hasValue = args[i], true
+		}
+		if err := o.setFlag(flag, value, hasValue, negated); err != nil {
+			return nil, nil, err
+		}
+	}
+	return o, rest, nil
+}
+
+// resolveFlag returns the full name of a git-describe flag, which may be
+// abbreviated to any unambiguous prefix.
+func resolveFlag(name string) (string, error) {
+	if _, ok := describeFlagArgs[name]; ok {
+		return name, nil
+	}
+	var match string
+	for flag := range describeFlagArgs {
+		if name == "" || !strings.HasPrefix(flag, name) {
+			continue
+		}
+		if match != "" {
+			return "", errors.New("ambiguous option: --" + name)
+		}
+		match = flag
+	}
+	if match == "" {
+		return "", errors.New("unknown option: --" + name)
+	}
+	return match, nil
+}
+
+// setFlag sets the option for a single resolved flag.
+func (o *DescribeOptions) setFlag(flag, value string, hasValue, negated bool) error {
+	switch flag {
+	case "dirty":
+		o.DirtyMark = optionalMark(value, hasValue, negated, "-dirty")
+	case "broken":
+		o.BrokenMark = optionalMark(value, hasValue, negated, "-broken")
+	case "all":
+		o.All = !negated
+	case "tags":
+		o.Tags = !negated
+	case "contains":
+		o.Contains = !negated
+	case "exact-match":
+		o.ExactMatch = !negated
+	case "debug":
+		o.Debug = !negated
+	case "long":
+		o.Long = !negated
+	case "always":
+		o.Always = !negated
+	case "first-parent":
+		o.FirstParent = !negated
+	case "abbrev":
+		switch {
+		case negated:
+			o.Abbrev = 0
+		case !hasValue:
+			o.Abbrev = DescribeOptionsDefaultAbbrev
+		default:
+			n, err := parseUintFlag(flag, value)
+			if err != nil {
+				return err
+			}
+			o.Abbrev = n
+		}
+	case "candidates":
+		if negated {
+			o.Candidates = 0
+			break
+		}
+		n, err := parseUintFlag(flag, value)
+		if err != nil {
+			return err
+		}
+		o.Candidates = n
+	case "match":
+		if negated {
+			o.MatchPattern, o.MatchPatterns = "", nil
+		} else {
+			addPattern(&o.MatchPattern, &o.MatchPatterns, value)
+		}
+	case "exclude":
+		if negated {
+			o.ExcludePattern, o.ExcludePatterns = "", nil
+		} else {
+			addPattern(&o.ExcludePattern, &o.ExcludePatterns, value)
+		}
+	}
+	return nil
+}
+
+// addPattern accumulates a repeatable pattern flag, setting the single pattern
+// field first and appending any further patterns to its list.
+func addPattern(pattern *string, patterns *[]string, value string) {
+	if *pattern == "" && len(*patterns) == 0 {
+		*pattern = value
+		return
+	}
+	*patterns = append(*patterns, value)
+}
+
+// optionalMark returns the mark set by a flag with an optional value, such as
+// --dirty[=<mark>].
+func optionalMark(value string, hasValue, negated bool, def string) string {
+	switch {
+	case negated:
+		return ""
+	case !hasValue:
+		return def
+	}
+	return value
+}
+
+// parseUintFlag parses the numeric value of a flag.
+func parseUintFlag(flag, value string) (uint, error) {
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, errors.New("option --" + flag + " expects a non-negative number: " + value)
+	}
+	return uint(n), nil
+}
+
+// isNumber reports whether s consists only of decimal digits.
+func isNumber(s string) bool {
+	return s != "" && strings.IndexFunc(s, isNotDigit) == -1
+}
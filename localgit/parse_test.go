@@ -0,0 +1,190 @@
+package localgit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     *DescribeOptions
+		wantRest []string
+		wantErr  bool
+	}{
+		{
+			name: "no arguments",
+			args: nil,
+			want: NewDescribeOptions(),
+		},
+		{
+			name:     "toggles and commit-ishes",
+			args:     []string{"--tags", "HEAD~1", "--first-parent", "main"},
+			want:     NewDescribeOptions().Set(func(o *DescribeOptions) { o.Tags = true; o.FirstParent = true }),
+			wantRest: []string{"HEAD~1", "main"},
+		},
+		{
+			name: "abbrev forms",
+			args: []string{"--abbrev=12"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.Abbrev = 12 }),
+		},
+		{
+			name:     "abbrev separate value",
+			args:     []string{"--abbrev", "0", "HEAD"},
+			want:     NewDescribeOptions().Set(func(o *DescribeOptions) { o.Abbrev = 0 }),
+			wantRest: []string{"HEAD"},
+		},
+		{
+			name:     "abbrev without value",
+			args:     []string{"--abbrev=3", "--abbrev", "HEAD"},
+			want:     NewDescribeOptions(),
+			wantRest: []string{"HEAD"},
+		},
+		{
+			name: "no-abbrev",
+			args: []string{"--no-abbrev"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.Abbrev = 0 }),
+		},
+		{
+			name: "candidates separate value",
+			args: []string{"--candidates", "3"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.Candidates = 3 }),
+		},
+		{
+			name: "repeated patterns",
+			args: []string{"--match", "v*", "--match=release-*", "--exclude=*-rc*"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.MatchPattern = "v*"
+				o.MatchPatterns = []string{"release-*"}
+				o.ExcludePattern = "*-rc*"
+			}),
+		},
+		{
+			name: "no-match clears patterns",
+			args: []string{"--match", "v*", "--no-match", "--match=release-*"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.MatchPattern = "release-*"
+			}),
+		},
+		{
+			name: "dirty without value",
+			args: []string{"--dirty", "--broken"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.DirtyMark = "-dirty"; o.BrokenMark = "-broken" }),
+		},
+		{
+			name: "dirty with value",
+			args: []string{"--dirty=.modified"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.DirtyMark = ".modified" }),
+		},
+		{
+			name:     "dirty does not consume next argument",
+			args:     []string{"--dirty", "HEAD"},
+			want:     NewDescribeOptions().Set(func(o *DescribeOptions) { o.DirtyMark = "-dirty" }),
+			wantRest: []string{"HEAD"},
+		},
+		{
+			name: "negated toggle",
+			args: []string{"--tags", "--no-tags"},
+			want: NewDescribeOptions(),
+		},
+		{
+			name: "abbreviated flag names",
+			args: []string{"--exa", "--first", "--cand=2"},
+			want: NewDescribeOptions().Set(func(o *DescribeOptions) { o.ExactMatch = true; o.FirstParent = true; o.Candidates = 2 }),
+		},
+		{
+			name:     "double dash ends options",
+			args:     []string{"--long", "--", "--tags"},
+			want:     NewDescribeOptions().Set(func(o *DescribeOptions) { o.Long = true }),
+			wantRest: []string{"--tags"},
+		},
+		{
+			name:    "ambiguous abbreviation",
+			args:    []string{"--a"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown option",
+			args:    []string{"--frobnicate"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown switch",
+			args:    []string{"-x"},
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			args:    []string{"--match"},
+			wantErr: true,
+		},
+		{
+			name:    "unexpected value",
+			args:    []string{"--tags=yes"},
+			wantErr: true,
+		},
+		{
+			name:    "negative number",
+			args:    []string{"--candidates=-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotRest, err := ParseFlags(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFlags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFlags() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotRest, tt.wantRest) {
+				t.Errorf("ParseFlags() rest = %v, want %v", gotRest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestParseFlags_roundTrip(t *testing.T) {
+	tests := []*DescribeOptions{
+		NewDescribeOptions(),
+		NewDescribeOptions().Set(func(o *DescribeOptions) {
+			o.DirtyMark = "-dirty"
+			o.BrokenMark = ".broken"
+			o.All = true
+			o.Tags = true
+			o.Contains = true
+			o.Abbrev = 0
+			o.Candidates = 0
+			o.ExactMatch = true
+			o.Debug = true
+			o.Long = true
+			o.MatchPattern = "v*"
+			o.MatchPatterns = []string{"release-*"}
+			o.ExcludePattern = "*-rc*"
+			o.Always = true
+			o.FirstParent = true
+		}),
+		NewDescribeOptions().Set(func(o *DescribeOptions) {
+			o.Abbrev = 40
+			o.Candidates = 20
+			o.MatchPattern = "v[0-9]*"
+		}),
+	}
+	for _, want := range tests {
+		flags := want.Flags()
+		got, rest, err := ParseFlags(flags)
+		if err != nil {
+			t.Errorf("ParseFlags(%v) error = %v", flags, err)
+			continue
+		}
+		if len(rest) != 0 {
+			t.Errorf("ParseFlags(%v) rest = %v, want none", flags, rest)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseFlags(%v) = %+v, want %+v", flags, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package localgit
+
+import "errors"
+
+// Validate returns a descriptive error if the options contain a combination
+// which git-describe would reject, or in which it would silently ignore some
+// of the options.
+func (o *DescribeOptions) Validate() error {
+	switch {
+	case o.Long && o.Abbrev == 0:
+		return errors.New("--long is incompatible with --abbrev=0")
+	case o.ExactMatch && o.Candidates != 0 && o.Candidates != DescribeOptionsDefaultCandidates:
+		return errors.New("--exact-match is incompatible with --candidates, as it implies --candidates=0")
+	}
+	if !o.Contains {
+		return nil
+	}
+	// --contains is handled by git name-rev, which ignores many options
+	switch {
+	case o.All && (len(o.matchPatterns()) > 0 || len(o.excludePatterns()) > 0):
+		return errors.New("--match and --exclude are ignored by --contains with --all")
+	case o.DirtyMark != "" || o.BrokenMark != "":
+		return errors.New("--dirty and --broken are incompatible with --contains")
+	case o.Long:
+		return errors.New("--long is incompatible with --contains")
+	case o.FirstParent:
+		return errors.New("--first-parent is incompatible with --contains")
+	case o.ExactMatch || o.Candidates != DescribeOptionsDefaultCandidates:
+		return errors.New("--exact-match and --candidates are incompatible with --contains")
+	}
+	return nil
+}
+
+// ValidateArgs validates the options as Validate does, along with the
+// commit-ishes they would be used to describe, as git-describe refuses to check
+// the state of the working tree when given any commit-ish.
+func (o *DescribeOptions) ValidateArgs(commitishes []string) error {
+	if len(commitishes) > 0 && o.DirtyMark != "" {
+		return errors.New("--dirty is incompatible with commit-ishes")
+	}
+	if len(commitishes) > 0 && o.BrokenMark != "" {
+		return errors.New("--broken is incompatible with commit-ishes")
+	}
+	return o.Validate()
+}
@@ -0,0 +1,86 @@
+package localgit
+
+import "testing"
+
+func TestDescribeOptions_ValidateArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        *DescribeOptions
+		commitishes []string
+		wantErr     bool
+	}{
+		{
+			name: "defaults",
+			opts: NewDescribeOptions(),
+		},
+		{
+			name: "long with abbrev",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) { o.Long = true; o.Abbrev = 40 }),
+		},
+		{
+			name:    "long without abbrev",
+			opts:    NewDescribeOptions().Set(func(o *DescribeOptions) { o.Long = true; o.Abbrev = 0 }),
+			wantErr: true,
+		},
+		{
+			name: "exact match with zero candidates",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) { o.ExactMatch = true; o.Candidates = 0 }),
+		},
+		{
+			name:    "exact match with candidates",
+			opts:    NewDescribeOptions().Set(func(o *DescribeOptions) { o.ExactMatch = true; o.Candidates = 3 }),
+			wantErr: true,
+		},
+		{
+			name: "contains with all",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) { o.Contains = true; o.All = true }),
+		},
+		{
+			name: "contains with all and patterns",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.Contains = true
+				o.All = true
+				o.MatchPattern = "v*"
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "contains with dirty",
+			opts:    NewDescribeOptions().Set(func(o *DescribeOptions) { o.Contains = true; o.DirtyMark = "-dirty" }),
+			wantErr: true,
+		},
+		{
+			name:    "contains with long",
+			opts:    NewDescribeOptions().Set(func(o *DescribeOptions) { o.Contains = true; o.Long = true }),
+			wantErr: true,
+		},
+		{
+			name:    "contains with candidates",
+			opts:    NewDescribeOptions().Set(func(o *DescribeOptions) { o.Contains = true; o.Candidates = 3 }),
+			wantErr: true,
+		},
+		{
+			name: "dirty without commit-ish",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) { o.DirtyMark = "-dirty" }),
+		},
+		{
+			name:        "dirty with commit-ish",
+			opts:        NewDescribeOptions().Set(func(o *DescribeOptions) { o.DirtyMark = "-dirty" }),
+			commitishes: []string{"HEAD~1"},
+			wantErr:     true,
+		},
+		{
+			name:        "broken with commit-ish",
+			opts:        NewDescribeOptions().Set(func(o *DescribeOptions) { o.BrokenMark = "-broken" }),
+			commitishes: []string{"HEAD~1"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.ValidateArgs(tt.commitishes); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
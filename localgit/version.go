@@ -0,0 +1,149 @@
+package localgit
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version is the version of a git installation.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// Versions of git which introduced describe features that DescribeOptions may
+// require.
+var (
+	// --exclude, and accumulating multiple --match patterns
+	VersionDescribeExclude = Version{2, 13, 0}
+	// --broken
+	VersionDescribeBroken = Version{2, 13, 0}
+)
+
+// ParseVersion parses the output of `git --version`, e.g. "git version 2.39.2"
+// or "git version 2.24.3 (Apple Git-128)". Any components beyond the patch
+// version, such as in "2.20.1.windows.1", are ignored.
+func ParseVersion(output string) (Version, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return Version{}, errors.New("unable to parse git version: " + output)
+	}
+
+	// leading digits only, e.g. for release candidates like "2.13.0-rc1"
+	var v Version
+	parts := strings.SplitN(fields[2], ".", 4)
+	for i, dst := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if i >= len(parts) {
+			break
+		}
+		digits := parts[i]
+		if end := strings.IndexFunc(digits, isNotDigit); end != -1 {
+			digits = digits[:end]
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return Version{}, errors.New("unable to parse git version: " + output)
+		}
+		*dst = n
+	}
+	return v, nil
+}
+
+func isNotDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
+
+// String returns the version in the form "2.39.2".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or later than min.
+func (v Version) AtLeast(min Version) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+var (
+	gitVersionOnce sync.Once
+	gitVersion     Version
+	gitVersionErr  error
+)
+
+// GitVersion returns the version of the git found in $PATH. It is only run
+// once, with the result cached for the lifetime of the process.
+func GitVersion() (Version, error) {
+	gitVersionOnce.Do(func() {
+		output, err := exec.Command("git", "--version").Output()
+		if err != nil {
+			gitVersionErr = err
+			return
+		}
+		gitVersion, gitVersionErr = ParseVersion(string(output))
+	})
+	return gitVersion, gitVersionErr
+}
+
+// HasVersion reports whether the git found in $PATH is at least version min.
+func HasVersion(min Version) (bool, error) {
+	v, err := GitVersion()
+	if err != nil {
+		return false, err
+	}
+	return v.AtLeast(min), nil
+}
+
+// UnsupportedError is returned when a git operation requires a later version
+// of git than is installed.
+type UnsupportedError struct {
+	// The flag which is unsupported, e.g. "--exclude"
+	Flag string
+	// The minimum version of git supporting Flag
+	Required Version
+	// The version of git installed
+	Found Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("git describe %v requires git %v or later (found %v)",
+		e.Flag, e.Required, e.Found)
+}
+
+// CheckVersion returns an *UnsupportedError if any of the options require a
+// later version of git than v.
+func (o *DescribeOptions) CheckVersion(v Version) error {
+	unsupported := func(flag string, required Version) error {
+		return &UnsupportedError{Flag: flag, Required: required, Found: v}
+	}
+	switch {
+	case len(o.excludePatterns()) > 0 && !v.AtLeast(VersionDescribeExclude):
+		return unsupported("--exclude", VersionDescribeExclude)
+	case len(o.matchPatterns()) > 1 && !v.AtLeast(VersionDescribeExclude):
+		return unsupported("with multiple --match", VersionDescribeExclude)
+	case o.BrokenMark != "" && !v.AtLeast(VersionDescribeBroken):
+		return unsupported("--broken", VersionDescribeBroken)
+	}
+	return nil
+}
+
+// Check returns an *UnsupportedError if any of the options are not supported by
+// the git found in $PATH. The version of git is only checked when the options
+// require a version with known incompatibilities.
+func (o *DescribeOptions) Check() error {
+	if o.CheckVersion(Version{}) == nil {
+		return nil
+	}
+	v, err := GitVersion()
+	if err != nil {
+		return err
+	}
+	return o.CheckVersion(v)
+}
@@ -0,0 +1,121 @@
+package localgit
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    Version
+		wantErr bool
+	}{
+		{"release", "git version 2.39.2\n", Version{2, 39, 2}, false},
+		{"apple", "git version 2.24.3 (Apple Git-128)\n", Version{2, 24, 3}, false},
+		{"windows", "git version 2.20.1.windows.1\n", Version{2, 20, 1}, false},
+		{"release candidate", "git version 2.13.0-rc1\n", Version{2, 13, 0}, false},
+		{"old release candidate", "git version 1.8.5.rc3\n", Version{1, 8, 5}, false},
+		{"no patch", "git version 2.40\n", Version{2, 40, 0}, false},
+		{"not git", "hub version 2.14.2\n", Version{}, true},
+		{"garbage", "git version unknown\n", Version{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_AtLeast(t *testing.T) {
+	tests := []struct {
+		v, min Version
+		want   bool
+	}{
+		{Version{2, 13, 0}, Version{2, 13, 0}, true},
+		{Version{2, 13, 1}, Version{2, 13, 0}, true},
+		{Version{2, 12, 5}, Version{2, 13, 0}, false},
+		{Version{3, 0, 0}, Version{2, 13, 0}, true},
+		{Version{1, 99, 99}, Version{2, 0, 0}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.v.AtLeast(tt.min); got != tt.want {
+			t.Errorf("%v.AtLeast(%v) = %v, want %v", tt.v, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeOptions_CheckVersion(t *testing.T) {
+	old := Version{2, 11, 0}
+	tests := []struct {
+		name     string
+		opts     *DescribeOptions
+		v        Version
+		wantFlag string
+	}{
+		{
+			name: "defaults",
+			opts: NewDescribeOptions(),
+			v:    old,
+		},
+		{
+			name: "single match",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.MatchPattern = "v*"
+			}),
+			v: old,
+		},
+		{
+			name: "multiple match",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.MatchPattern = "v*"
+				o.MatchPatterns = []string{"release-*"}
+			}),
+			v:        old,
+			wantFlag: "with multiple --match",
+		},
+		{
+			name: "exclude",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.ExcludePattern = "*-rc*"
+			}),
+			v:        old,
+			wantFlag: "--exclude",
+		},
+		{
+			name: "exclude supported",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.ExcludePattern = "*-rc*"
+			}),
+			v: Version{2, 13, 0},
+		},
+		{
+			name: "broken",
+			opts: NewDescribeOptions().Set(func(o *DescribeOptions) {
+				o.BrokenMark = "-broken"
+			}),
+			v:        old,
+			wantFlag: "--broken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.CheckVersion(tt.v)
+			if tt.wantFlag == "" {
+				if err != nil {
+					t.Errorf("CheckVersion() = %v, want nil", err)
+				}
+				return
+			}
+			uerr, ok := err.(*UnsupportedError)
+			if !ok || uerr.Flag != tt.wantFlag {
+				t.Errorf("CheckVersion() = %v, want UnsupportedError for %v", err, tt.wantFlag)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package semverdesc
+
+import (
+	"encoding/json"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// Report is the JSON representation of DescribeResults, carrying both the
+// structured results and their formatted strings, so that consumers need not
+// parse either.
+//
+// The schema is stable: fields are only ever added, and all of the fields
+// listed here are always present, using null for a Semver which is not
+// available and empty arrays rather than null for Alternatives.
+type Report struct {
+	// The name of the matched tag
+	Tag string `json:"tag"`
+	// The SemVer components of the tag, or null if it is not valid SemVer
+	Semver *ReportSemver `json:"semver"`
+	// Number of commits ahead of the tag
+	Distance uint `json:"distance"`
+	// The full hash of the described commit
+	Hash string `json:"hash"`
+	// The hash abbreviated as in the formatted strings, or to the default of
+	// 7 digits if they omit it
+	AbbrevHash string `json:"abbrev_hash"`
+	// Whether the working tree has local modifications
+	Dirty bool `json:"dirty"`
+	// The hash of the local modifications, or "" if not computed
+	DirtyHash string `json:"dirty_hash"`
+	// Whether the state of the working tree could not be determined
+	Broken bool `json:"broken"`
+	// Whether the results may be inaccurate, e.g. for a shallow clone
+	Approximate bool `json:"approximate"`
+	// Other tags considered by a selection policy but not chosen
+	Alternatives []string `json:"alternatives"`
+	// The results formatted in semver describe format
+	Version string `json:"version"`
+	// The results formatted in legacy git describe format
+	Legacy string `json:"legacy"`
+	// The options the formatted strings were formatted with
+	Format ReportFormat `json:"format"`
+}
+
+// ReportSemver are the SemVer components of a tag in a Report.
+type ReportSemver struct {
+	// "v" if the tag is prefixed with one, otherwise ""
+	Prefix     string   `json:"prefix"`
+	Major      uint64   `json:"major"`
+	Minor      uint64   `json:"minor"`
+	Patch      uint64   `json:"patch"`
+	Prerelease []string `json:"prerelease"`
+	Build      []string `json:"build"`
+}
+
+// ReportFormat are the FormatOptions used for a Report.
+type ReportFormat struct {
+	Abbrev          uint   `json:"abbrev"`
+	Long            bool   `json:"long"`
+	DirtyMark       string `json:"dirty_mark"`
+	BrokenMark      string `json:"broken_mark"`
+	DirtyHashAbbrev uint   `json:"dirty_hash_abbrev"`
+}
+
+// NewReport returns the Report for the results, formatted with opts.
+func NewReport(dr *DescribeResults, opts FormatOptions) *Report {
+	r := &Report{
+		Tag:          dr.TagName,
+		Distance:     dr.Distance,
+		Hash:         dr.HashStr,
+		Dirty:        dr.Dirty,
+		DirtyHash:    dr.DirtyHash,
+		Broken:       dr.Broken,
+		Approximate:  dr.Approximate,
+		Alternatives: append([]string{}, dr.Alternatives...),
+		Version:      dr.Format(opts),
+		Legacy:       dr.FormatLegacy(opts),
+		Format: ReportFormat{
+			Abbrev:          opts.Abbrev,
+			Long:            opts.Long,
+			DirtyMark:       opts.DirtyMark,
+			BrokenMark:      opts.BrokenMark,
+			DirtyHashAbbrev: opts.DirtyHashAbbrev,
+		},
+	}
+
+	abbrevOpts := opts
+	if abbrevOpts.Abbrev == 0 {
+		abbrevOpts.Abbrev = DefaultFormatAbbrev
+	}
+	r.AbbrevHash = dr.HashStr[:effectiveAbbrev(dr.HashStr, abbrevOpts)]
+
+	if v, err := semver.Parse(dr.TagName); err == nil {
+		r.Semver = &ReportSemver{
+			Prefix:     v.Prefix,
+			Major:      v.Major,
+			Minor:      v.Minor,
+			Patch:      v.Patch,
+			Prerelease: append([]string{}, v.Prerelease...),
+			Build:      append([]string{}, v.Build...),
+		}
+	}
+	return r
+}
+
+// MarshalJSON implements json.Marshaler, encoding the results as a Report
+// formatted with DefaultFormatOptions. As with its other methods, it has a
+// pointer receiver, so results embedded by value must be addressable (e.g.
+// by marshaling a pointer to the struct containing them) to be encoded so.
+func (dr *DescribeResults) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewReport(dr, DefaultFormatOptions()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding either a Report as
+// encoded by MarshalJSON, or a string in semver describe format as decoded by
+// UnmarshalText.
+func (dr *DescribeResults) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return dr.UnmarshalText([]byte(s))
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	*dr = DescribeResults{
+		TagName:     r.Tag,
+		Distance:    r.Distance,
+		HashStr:     r.Hash,
+		Dirty:       r.Dirty,
+		DirtyHash:   r.DirtyHash,
+		Broken:      r.Broken,
+		Approximate: r.Approximate,
+	}
+	if len(r.Alternatives) > 0 {
+		dr.Alternatives = r.Alternatives
+	}
+	return nil
+}
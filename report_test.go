@@ -0,0 +1,85 @@
+package semverdesc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDescribeResults_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		desc DescribeResults
+		want string
+	}{
+		{
+			name: "semver tag",
+			desc: DescribeResults{
+				TagName:  "v1.2.3-rc.1",
+				Distance: 4,
+				HashStr:  "abc1234072d51458a534ca7e0ec7c181d8475477",
+				Dirty:    true,
+			},
+			want: `{"tag":"v1.2.3-rc.1",` +
+				`"semver":{"prefix":"v","major":1,"minor":2,"patch":3,"prerelease":["rc","1"],"build":[]},` +
+				`"distance":4,"hash":"abc1234072d51458a534ca7e0ec7c181d8475477","abbrev_hash":"abc1234",` +
+				`"dirty":true,"dirty_hash":"","broken":false,"approximate":false,"alternatives":[],` +
+				`"version":"v1.2.3-rc.1+4.gabc1234","legacy":"v1.2.3-rc.1-4-gabc1234",` +
+				`"format":{"abbrev":7,"long":false,"dirty_mark":"","broken_mark":"","dirty_hash_abbrev":0}}`,
+		},
+		{
+			name: "non-semver tag",
+			desc: DescribeResults{
+				TagName:      "latest",
+				HashStr:      "abc1234072d51458a534ca7e0ec7c181d8475477",
+				Alternatives: []string{"stable"},
+			},
+			want: `{"tag":"latest","semver":null,` +
+				`"distance":0,"hash":"abc1234072d51458a534ca7e0ec7c181d8475477","abbrev_hash":"abc1234",` +
+				`"dirty":false,"dirty_hash":"","broken":false,"approximate":false,"alternatives":["stable"],` +
+				`"version":"latest","legacy":"latest",` +
+				`"format":{"abbrev":7,"long":false,"dirty_mark":"","broken_mark":"","dirty_hash_abbrev":0}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(&tt.desc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+
+			// an addressable value, e.g. a field of a struct marshaled by
+			// pointer, is encoded the same way
+			got, err = json.Marshal(&struct {
+				Version DescribeResults `json:"version"`
+			}{tt.desc})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := `{"version":` + tt.want + `}`; string(got) != want {
+				t.Errorf("MarshalJSON() of value = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestNewReport_abbrevHash(t *testing.T) {
+	d := &DescribeResults{
+		TagName:  "v1.2.3",
+		Distance: 4,
+		HashStr:  "abc1234072d51458a534ca7e0ec7c181d8475477",
+	}
+	for _, tt := range []struct {
+		abbrev uint
+		want   string
+	}{
+		{0, "abc1234"},
+		{10, "abc1234072"},
+	} {
+		if got := NewReport(d, FormatOptions{Abbrev: tt.abbrev}).AbbrevHash; got != tt.want {
+			t.Errorf("NewReport() with Abbrev %d AbbrevHash = %v, want %v", tt.abbrev, got, tt.want)
+		}
+	}
+}
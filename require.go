@@ -0,0 +1,149 @@
+package semverdesc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mroth/semverdesc/semver"
+)
+
+// Requirement is a condition DescribeResults must meet, e.g. before publishing
+// a release from them.
+type Requirement int
+
+// Available requirements.
+const (
+	// RequireExact requires the results to be an exact match of a tag.
+	RequireExact Requirement = iota + 1
+	// RequireClean requires the working tree to have no local modifications,
+	// and its state to be known.
+	RequireClean
+	// RequireRelease requires the results to be a clean exact match of a
+	// SemVer tag for a release, i.e. one without a pre-release.
+	RequireRelease
+	// RequirePrerelease requires the results to be a clean exact match of a
+	// SemVer tag for a pre-release.
+	RequirePrerelease
+)
+
+var requirementNames = map[Requirement]string{
+	RequireExact:      "exact",
+	RequireClean:      "clean",
+	RequireRelease:    "release",
+	RequirePrerelease: "prerelease",
+}
+
+// String returns the name of the requirement, as accepted by
+// ParseRequirement.
+func (r Requirement) String() string {
+	if name, ok := requirementNames[r]; ok {
+		return name
+	}
+	return "Requirement(" + strconv.Itoa(int(r)) + ")"
+}
+
+// ParseRequirement returns the Requirement with the given name, one of
+// "exact", "clean", "release" or "prerelease".
+func ParseRequirement(name string) (Requirement, error) {
+	for r, n := range requirementNames {
+		if n == name {
+			return r, nil
+		}
+	}
+	return 0, errors.New("unknown requirement: " + name)
+}
+
+// Failure is the reason DescribeResults do not meet a Requirement.
+type Failure int
+
+// Reasons for failing a requirement.
+const (
+	// FailNotExact is when the results are not an exact match of a tag.
+	FailNotExact Failure = iota + 1
+	// FailDirty is when the working tree has local modifications.
+	FailDirty
+	// FailBroken is when the state of the working tree is unknown.
+	FailBroken
+	// FailNotSemver is when the tag is not valid SemVer.
+	FailNotSemver
+	// FailPrerelease is when the tag is for a pre-release, not a release.
+	FailPrerelease
+	// FailNotPrerelease is when the tag is for a release, not a pre-release.
+	FailNotPrerelease
+)
+
+// RequirementError is returned when DescribeResults do not meet a
+// Requirement.
+type RequirementError struct {
+	// The requirement which was not met
+	Requirement Requirement
+	// Why the requirement was not met
+	Failure Failure
+	// The results which did not meet the requirement
+	Results *DescribeResults
+}
+
+func (e *RequirementError) Error() string {
+	var reason string
+	switch e.Failure {
+	case FailNotExact:
+		reason = fmt.Sprintf("%d commits since tag %v", e.Results.Distance, e.Results.TagName)
+	case FailDirty:
+		reason = "working tree has local modifications"
+	case FailBroken:
+		reason = "state of the working tree could not be determined"
+	case FailNotSemver:
+		reason = fmt.Sprintf("tag %v is not valid SemVer", e.Results.TagName)
+	case FailPrerelease:
+		reason = fmt.Sprintf("tag %v is a pre-release", e.Results.TagName)
+	case FailNotPrerelease:
+		reason = fmt.Sprintf("tag %v is not a pre-release", e.Results.TagName)
+	default:
+		reason = "Failure(" + strconv.Itoa(int(e.Failure)) + ")"
+	}
+	return fmt.Sprintf("%v does not meet requirement %v: %v",
+		e.Results.String(), e.Requirement, reason)
+}
+
+// Require returns a *RequirementError if the results do not meet the
+// requirement r.
+//
+// Note that results of describing a commit-ish other than the working tree are
+// never Dirty, so always meet RequireClean.
+func (dr *DescribeResults) Require(r Requirement) error {
+	if failure := dr.check(r); failure != 0 {
+		return &RequirementError{Requirement: r, Failure: failure, Results: dr}
+	}
+	return nil
+}
+
+// check returns why the results do not meet the requirement r, or 0 if they
+// do.
+func (dr *DescribeResults) check(r Requirement) Failure {
+	if r == RequireClean || r == RequireRelease || r == RequirePrerelease {
+		switch {
+		case dr.Broken:
+			return FailBroken
+		case dr.Dirty:
+			return FailDirty
+		}
+	}
+	if r == RequireExact || r == RequireRelease || r == RequirePrerelease {
+		if dr.Distance != 0 {
+			return FailNotExact
+		}
+	}
+	if r == RequireRelease || r == RequirePrerelease {
+		v, err := semver.Parse(dr.TagName)
+		switch {
+		case err != nil:
+			return FailNotSemver
+		case r == RequireRelease && v.IsPrerelease():
+			return FailPrerelease
+		case r == RequirePrerelease && !v.IsPrerelease():
+			return FailNotPrerelease
+		}
+	}
+	return 0
+}
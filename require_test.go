@@ -0,0 +1,64 @@
+package semverdesc
+
+import "testing"
+
+func TestDescribeResults_Require(t *testing.T) {
+	const hash = "71dd5072d51458a534ca7e0ec7c181d84754774d"
+	var (
+		release    = DescribeResults{TagName: "v1.2.3", HashStr: hash}
+		prerelease = DescribeResults{TagName: "v1.2.3-rc.1", HashStr: hash}
+		ahead      = DescribeResults{TagName: "v1.2.3", Distance: 2, HashStr: hash}
+		dirty      = DescribeResults{TagName: "v1.2.3", HashStr: hash, Dirty: true}
+		broken     = DescribeResults{TagName: "v1.2.3", HashStr: hash, Broken: true}
+		nonSemver  = DescribeResults{TagName: "latest", HashStr: hash}
+	)
+	tests := []struct {
+		name        string
+		desc        DescribeResults
+		requirement Requirement
+		want        Failure
+	}{
+		{"release meets release", release, RequireRelease, 0},
+		{"release meets exact", release, RequireExact, 0},
+		{"release meets clean", release, RequireClean, 0},
+		{"prerelease meets prerelease", prerelease, RequirePrerelease, 0},
+		{"prerelease fails release", prerelease, RequireRelease, FailPrerelease},
+		{"release fails prerelease", release, RequirePrerelease, FailNotPrerelease},
+		{"ahead fails exact", ahead, RequireExact, FailNotExact},
+		{"ahead fails release", ahead, RequireRelease, FailNotExact},
+		{"ahead meets clean", ahead, RequireClean, 0},
+		{"dirty fails clean", dirty, RequireClean, FailDirty},
+		{"dirty fails release", dirty, RequireRelease, FailDirty},
+		{"dirty meets exact", dirty, RequireExact, 0},
+		{"broken fails clean", broken, RequireClean, FailBroken},
+		{"non-semver fails release", nonSemver, RequireRelease, FailNotSemver},
+		{"non-semver meets exact", nonSemver, RequireExact, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.desc.Require(tt.requirement)
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("Require() = %v, want nil", err)
+				}
+				return
+			}
+			rerr, ok := err.(*RequirementError)
+			if !ok || rerr.Failure != tt.want {
+				t.Errorf("Require() = %v, want failure %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequirement(t *testing.T) {
+	for r := range requirementNames {
+		got, err := ParseRequirement(r.String())
+		if err != nil || got != r {
+			t.Errorf("ParseRequirement(%q) = %v, %v, want %v", r.String(), got, err, r)
+		}
+	}
+	if _, err := ParseRequirement("perfect"); err == nil {
+		t.Error("ParseRequirement(\"perfect\") expected error")
+	}
+}
@@ -0,0 +1,133 @@
+package semver
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Part is the part of a version incremented by Bump.
+type Part int
+
+// Parts of a version which may be bumped.
+const (
+	// BumpMajor increments the major version, e.g. 1.2.3 to 2.0.0.
+	BumpMajor Part = iota
+	// BumpMinor increments the minor version, e.g. 1.2.3 to 1.3.0.
+	BumpMinor
+	// BumpPatch increments the patch version, e.g. 1.2.3 to 1.2.4.
+	BumpPatch
+	// BumpPrerelease increments the pre-release, e.g. 1.2.3-rc.1 to
+	// 1.2.3-rc.2.
+	BumpPrerelease
+)
+
+var partNames = map[Part]string{
+	BumpMajor:      "major",
+	BumpMinor:      "minor",
+	BumpPatch:      "patch",
+	BumpPrerelease: "prerelease",
+}
+
+// String returns the name of the part, as accepted by ParsePart.
+func (p Part) String() string {
+	if name, ok := partNames[p]; ok {
+		return name
+	}
+	return "Part(" + strconv.Itoa(int(p)) + ")"
+}
+
+// ParsePart returns the Part with the given name, one of "major", "minor",
+// "patch" or "prerelease".
+func ParsePart(name string) (Part, error) {
+	for p, n := range partNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return 0, errors.New("unknown version part: " + name)
+}
+
+// Bump returns the version following v when incrementing part, keeping the
+// Prefix of v and dropping any build metadata.
+//
+// If pre is set, the result is the first pre-release of that version using
+// pre as its identifier, e.g. 1.2.3 bumped by minor with pre "rc" is
+// 1.3.0-rc.1. Otherwise, the release of a pre-release is the next version
+// when it already has the part incremented, e.g. 1.3.0-rc.2 bumped by minor
+// (or patch) is 1.3.0, but bumped by major is 2.0.0.
+//
+// Bumping the pre-release increments its last numeric identifier, appending
+// one if there is none. If pre is set and differs from the first identifier,
+// the pre-release starts over using pre instead (e.g. 1.2.3-beta.2 to
+// 1.2.3-rc.1), which is an error if it would lower precedence. Bumping the
+// pre-release of a release requires pre, and first increments the patch
+// version, e.g. 1.2.3 to 1.2.4-rc.1.
+func Bump(v Version, part Part, pre string) (Version, error) {
+	if pre != "" && (!isIdentifier(pre) || isNumeric(pre)) {
+		return Version{}, errors.New("invalid pre-release identifier: " + pre)
+	}
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	// the release of a pre-release is only the next version if no new
+	// pre-release was requested
+	release := v.IsPrerelease() && pre == ""
+
+	switch part {
+	case BumpMajor:
+		if !release || v.Minor != 0 || v.Patch != 0 {
+			next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+		}
+	case BumpMinor:
+		if !release || v.Patch != 0 {
+			next.Minor, next.Patch = v.Minor+1, 0
+		}
+	case BumpPatch:
+		if !release {
+			next.Patch = v.Patch + 1
+		}
+	case BumpPrerelease:
+		return bumpPrerelease(v, next, pre)
+	default:
+		return Version{}, errors.New("unknown version part: " + part.String())
+	}
+	if pre != "" {
+		next.Prerelease = []string{pre, "1"}
+	}
+	return next, nil
+}
+
+// bumpPrerelease returns next, being the version core of v, with the
+// pre-release following that of v.
+func bumpPrerelease(v, next Version, pre string) (Version, error) {
+	if !v.IsPrerelease() {
+		if pre == "" {
+			return Version{}, errors.New("a pre-release identifier is required to start a pre-release of " + v.String())
+		}
+		next.Patch = v.Patch + 1
+		next.Prerelease = []string{pre, "1"}
+		return next, nil
+	}
+
+	if pre != "" && pre != v.Prerelease[0] {
+		next.Prerelease = []string{pre, "1"}
+		if Compare(next, v) <= 0 {
+			return Version{}, errors.New(next.String() + " would not follow " + v.String())
+		}
+		return next, nil
+	}
+
+	ids := append([]string(nil), v.Prerelease...)
+	for i := len(ids) - 1; i >= 0; i-- {
+		if !isNumeric(ids[i]) {
+			continue
+		}
+		n, err := strconv.ParseUint(ids[i], 10, 64)
+		if err != nil || n == ^uint64(0) {
+			return Version{}, errors.New("pre-release identifier out of range: " + ids[i])
+		}
+		ids[i] = strconv.FormatUint(n+1, 10)
+		next.Prerelease = ids
+		return next, nil
+	}
+	next.Prerelease = append(ids, "1")
+	return next, nil
+}
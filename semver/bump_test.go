@@ -0,0 +1,66 @@
+package semver
+
+import "testing"
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		part    Part
+		pre     string
+		want    string
+		wantErr bool
+	}{
+		{name: "major", v: "v1.2.3", part: BumpMajor, want: "v2.0.0"},
+		{name: "minor", v: "v1.2.3", part: BumpMinor, want: "v1.3.0"},
+		{name: "patch", v: "v1.2.3", part: BumpPatch, want: "v1.2.4"},
+		{name: "without prefix", v: "1.2.3", part: BumpPatch, want: "1.2.4"},
+		{name: "drops build metadata", v: "v1.2.3+build.5", part: BumpPatch, want: "v1.2.4"},
+		{name: "major with pre", v: "v1.2.3", part: BumpMajor, pre: "rc", want: "v2.0.0-rc.1"},
+		{name: "minor with pre", v: "v1.2.3", part: BumpMinor, pre: "rc", want: "v1.3.0-rc.1"},
+		{name: "patch with pre", v: "v1.2.3", part: BumpPatch, pre: "beta", want: "v1.2.4-beta.1"},
+		{name: "major releases major prerelease", v: "v2.0.0-rc.2", part: BumpMajor, want: "v2.0.0"},
+		{name: "major of minor prerelease", v: "v1.3.0-rc.2", part: BumpMajor, want: "v2.0.0"},
+		{name: "minor releases minor prerelease", v: "v1.3.0-rc.2", part: BumpMinor, want: "v1.3.0"},
+		{name: "minor of patch prerelease", v: "v1.2.4-rc.2", part: BumpMinor, want: "v1.3.0"},
+		{name: "patch releases prerelease", v: "v1.3.0-rc.2", part: BumpPatch, want: "v1.3.0"},
+		{name: "minor with pre of prerelease", v: "v1.3.0-rc.2", part: BumpMinor, pre: "rc", want: "v1.4.0-rc.1"},
+		{name: "prerelease", v: "v1.2.3-rc.1", part: BumpPrerelease, want: "v1.2.3-rc.2"},
+		{name: "prerelease same pre", v: "v1.2.3-rc.1", part: BumpPrerelease, pre: "rc", want: "v1.2.3-rc.2"},
+		{name: "prerelease last numeric", v: "v1.2.3-rc.1.x", part: BumpPrerelease, want: "v1.2.3-rc.2.x"},
+		{name: "prerelease numeric only", v: "1.2.3-9", part: BumpPrerelease, want: "1.2.3-10"},
+		{name: "prerelease without number", v: "v1.2.3-alpha", part: BumpPrerelease, want: "v1.2.3-alpha.1"},
+		{name: "prerelease new pre", v: "v1.2.3-beta.2", part: BumpPrerelease, pre: "rc", want: "v1.2.3-rc.1"},
+		{name: "prerelease of release", v: "v1.2.3", part: BumpPrerelease, pre: "rc", want: "v1.2.4-rc.1"},
+		{name: "prerelease of release without pre", v: "v1.2.3", part: BumpPrerelease, wantErr: true},
+		{name: "prerelease lowering precedence", v: "v1.2.3-rc.1", part: BumpPrerelease, pre: "beta", wantErr: true},
+		{name: "prerelease out of range", v: "v1.2.3-rc.18446744073709551615", part: BumpPrerelease, wantErr: true},
+		{name: "invalid pre", v: "v1.2.3", part: BumpMinor, pre: "rc.1", wantErr: true},
+		{name: "numeric pre", v: "v1.2.3", part: BumpMinor, pre: "1", wantErr: true},
+		{name: "unknown part", v: "v1.2.3", part: Part(42), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Bump(mustParse(t, tt.v), tt.part, tt.pre)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bump() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("Bump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePart(t *testing.T) {
+	for p := range partNames {
+		got, err := ParsePart(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParsePart(%q) = %v, %v, want %v", p.String(), got, err, p)
+		}
+	}
+	if _, err := ParsePart("micro"); err == nil {
+		t.Error("ParsePart(\"micro\") expected error")
+	}
+}
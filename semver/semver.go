@@ -0,0 +1,205 @@
+// Package semver parses and compares Semantic Versioning 2.0 version strings,
+// as they are commonly found in git tags. (https://semver.org)
+package semver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed Semantic Versioning 2.0 version.
+type Version struct {
+	// Prefix is the optional "v" preceding the version, as is conventional for
+	// git tags. It is preserved for String, but has no effect on precedence.
+	Prefix string
+
+	Major uint64
+	Minor uint64
+	Patch uint64
+
+	// The dot separated pre-release identifiers, if any.
+	Prerelease []string
+	// The dot separated build metadata identifiers, if any.
+	Build []string
+}
+
+// Parse parses s as a Semantic Versioning 2.0 version, optionally prefixed
+// with a "v".
+func Parse(s string) (Version, error) {
+	var v Version
+	rest := s
+	if strings.HasPrefix(rest, "v") {
+		v.Prefix, rest = "v", rest[1:]
+	}
+
+	if i := strings.IndexByte(rest, '+'); i != -1 {
+		build := strings.Split(rest[i+1:], ".")
+		for _, id := range build {
+			if !isIdentifier(id) {
+				return Version{}, errors.New("invalid build metadata: " + s)
+			}
+		}
+		v.Build, rest = build, rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '-'); i != -1 {
+		pre := strings.Split(rest[i+1:], ".")
+		for _, id := range pre {
+			if !isIdentifier(id) || (isNumeric(id) && hasLeadingZero(id)) {
+				return Version{}, errors.New("invalid pre-release: " + s)
+			}
+		}
+		v.Prerelease, rest = pre, rest[:i]
+	}
+
+	core := strings.Split(rest, ".")
+	if len(core) != 3 {
+		return Version{}, errors.New("invalid version core: " + s)
+	}
+	nums := make([]uint64, 3)
+	for i, c := range core {
+		if !isNumeric(c) || hasLeadingZero(c) {
+			return Version{}, errors.New("invalid version core: " + s)
+		}
+		n, err := strconv.ParseUint(c, 10, 64)
+		if err != nil {
+			return Version{}, errors.New("invalid version core: " + s)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// IsValid reports whether s is a valid Semantic Versioning 2.0 version,
+// optionally prefixed with a "v".
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// String returns the version in its canonical form, including any Prefix.
+func (v Version) String() string {
+	var b strings.Builder
+	b.WriteString(v.Prefix)
+	b.WriteString(strconv.FormatUint(v.Major, 10))
+	b.WriteByte('.')
+	b.WriteString(strconv.FormatUint(v.Minor, 10))
+	b.WriteByte('.')
+	b.WriteString(strconv.FormatUint(v.Patch, 10))
+	if len(v.Prerelease) > 0 {
+		b.WriteByte('-')
+		b.WriteString(strings.Join(v.Prerelease, "."))
+	}
+	if len(v.Build) > 0 {
+		b.WriteByte('+')
+		b.WriteString(strings.Join(v.Build, "."))
+	}
+	return b.String()
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers.
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns an integer comparing the precedence of two versions. The
+// result will be 0 if a and b have equal precedence, -1 if a < b, and +1 if
+// a > b. As per the specification, Prefix and Build are ignored.
+func Compare(a, b Version) int {
+	if c := compareUint(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// comparePrerelease compares pre-release identifiers per the specification: a
+// version without pre-release identifiers has higher precedence, otherwise each
+// identifier is compared in turn, with a larger set of identifiers having
+// higher precedence if all of the preceding ones are equal.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(a)), uint64(len(b)))
+}
+
+// compareIdentifier compares a single pre-release identifier: numeric
+// identifiers are compared numerically and always have lower precedence than
+// alphanumeric identifiers, which are compared lexically in ASCII sort order.
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		// compare by length first to avoid overflow on very large numbers,
+		// which is safe as leading zeroes are disallowed.
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// isIdentifier reports whether s is a non-empty string of [0-9A-Za-z-].
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether s is a non-empty string of [0-9].
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLeadingZero reports whether the numeric string s has a superfluous
+// leading zero.
+func hasLeadingZero(s string) bool {
+	return len(s) > 1 && s[0] == '0'
+}
@@ -0,0 +1,115 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Version
+		wantErr bool
+	}{
+		{
+			name: "release",
+			s:    "1.2.3",
+			want: Version{Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			name: "v prefix",
+			s:    "v1.2.3",
+			want: Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			name: "prerelease",
+			s:    "v1.0.0-rc.1",
+			want: Version{Prefix: "v", Major: 1, Prerelease: []string{"rc", "1"}},
+		},
+		{
+			name: "prerelease with hyphen",
+			s:    "1.0.0-x-y-z.--",
+			want: Version{Major: 1, Prerelease: []string{"x-y-z", "--"}},
+		},
+		{
+			name: "build metadata",
+			s:    "v0.2.1+15.gd71dd50",
+			want: Version{Prefix: "v", Minor: 2, Patch: 1, Build: []string{"15", "gd71dd50"}},
+		},
+		{
+			name: "prerelease and build metadata",
+			s:    "1.0.0-alpha+001",
+			want: Version{Major: 1, Prerelease: []string{"alpha"}, Build: []string{"001"}},
+		},
+		{name: "not a version", s: "latest", wantErr: true},
+		{name: "too few components", s: "v1.2", wantErr: true},
+		{name: "too many components", s: "1.2.3.4", wantErr: true},
+		{name: "leading zero", s: "1.02.3", wantErr: true},
+		{name: "numeric prerelease leading zero", s: "1.2.3-rc.01", wantErr: true},
+		{name: "empty prerelease identifier", s: "1.2.3-rc..1", wantErr: true},
+		{name: "empty build", s: "1.2.3+", wantErr: true},
+		{name: "invalid character", s: "1.2.3-rc_1", wantErr: true},
+		{name: "uppercase prefix", s: "V1.2.3", wantErr: true},
+		{name: "path prefixed", s: "services/billing/v1.2.3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.s {
+				t.Errorf("String() = %v, want %v", got.String(), tt.s)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// in order of precedence, per the example in the specification
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"v1.0.1",
+		"1.1.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, b := mustParse(t, ordered[i]), mustParse(t, ordered[j])
+			want := compareUint(uint64(i), uint64(j))
+			if got := Compare(a, b); got != want {
+				t.Errorf("Compare(%v, %v) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestCompare_ignoresPrefixAndBuild(t *testing.T) {
+	a, b := mustParse(t, "v1.2.3+15.gd71dd50"), mustParse(t, "1.2.3")
+	if got := Compare(a, b); got != 0 {
+		t.Errorf("Compare(%v, %v) = %v, want 0", a, b, got)
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
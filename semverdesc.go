@@ -17,6 +17,19 @@ type DescribeResults struct {
 	HashStr string
 	// Dirty is true if the working tree has local modifications.
 	Dirty bool
+	// DirtyHash is a hex encoded hash of the local modifications, if Dirty and
+	// it was computed, which distinguishes different dirty working trees.
+	DirtyHash string
+	// Broken is true if the repository is corrupt and it could not be
+	// determined whether the working tree has local modifications.
+	Broken bool
+	// Approximate is true if the results may be inaccurate, e.g. as the
+	// repository is a shallow clone with incomplete history.
+	Approximate bool
+	// Alternatives are the names of other tags which were considered but not
+	// selected for TagName, if the describe used a selection policy that
+	// considers multiple candidates. These have no effect on formatting.
+	Alternatives []string
 }
 
 // FormatOptions control the output when formatting a DescribeResults.
@@ -40,6 +53,12 @@ type FormatOptions struct {
 	// HEAD, the output is the same as "git describe HEAD". If the working tree
 	// has local modification DirtyMark is appended to it.
 	DirtyMark string
+	// If nonzero, the first <n> hexadecimal digits of the DirtyHash (when
+	// there is one) are appended after the DirtyMark, separated by a ".".
+	DirtyHashAbbrev uint
+	// If the repository is corrupt and the state of the working tree could not
+	// be determined, BrokenMark is appended instead.
+	BrokenMark string
 }
 
 // Defaults which differ from their zero values
@@ -90,27 +109,53 @@ func shouldUseShortFormat(dr *DescribeResults, opts FormatOptions) bool {
 
 // shortFormat is the same for both semver and legacy
 func shortFormat(dr *DescribeResults, opts FormatOptions) string {
-	return dr.TagName + dirtySuffix(dr, opts)
+	return dr.TagName + stateSuffix(dr, opts)
 }
 
 func semverLongFormat(dr *DescribeResults, opts FormatOptions) string {
-	abbrev := effectiveAbbrev(dr, opts)
+	abbrev := effectiveAbbrev(dr.HashStr, opts)
 	return fmt.Sprintf("%v+%v.g%v%v",
-		dr.TagName, dr.Distance, dr.HashStr[:abbrev], dirtySuffix(dr, opts))
+		dr.TagName, dr.Distance, dr.HashStr[:abbrev], stateSuffix(dr, opts))
 }
 
 func legacyLongFormat(dr *DescribeResults, opts FormatOptions) string {
-	abbrev := effectiveAbbrev(dr, opts)
+	abbrev := effectiveAbbrev(dr.HashStr, opts)
 	return fmt.Sprintf("%v-%v-g%v%v",
-		dr.TagName, dr.Distance, dr.HashStr[:abbrev], dirtySuffix(dr, opts))
+		dr.TagName, dr.Distance, dr.HashStr[:abbrev], stateSuffix(dr, opts))
+}
+
+// stateSuffix returns the suffix describing the state of the working tree, if
+// any. A Broken working tree takes priority, as its Dirty state is unknown.
+func stateSuffix(dr *DescribeResults, opts FormatOptions) string {
+	if dr.Broken {
+		return brokenSuffix(dr, opts)
+	}
+	return dirtySuffix(dr, opts)
 }
 
 // dirtySuffix returns the DirtyMark suffix if *DescribeResults are both Dirty
-// and FormatOptions has a nonzero DirtyMark.
+// and FormatOptions has a nonzero DirtyMark, followed by the abbreviated
+// DirtyHash if requested.
 func dirtySuffix(dr *DescribeResults, opts FormatOptions) string {
-	if dr.Dirty && opts.DirtyMark != "" {
+	if !dr.Dirty || opts.DirtyMark == "" {
+		return ""
+	}
+	abbrev := opts.DirtyHashAbbrev
+	if hashLen := uint(len(dr.DirtyHash)); hashLen < abbrev {
+		abbrev = hashLen
+	}
+	if abbrev == 0 {
 		return opts.DirtyMark
 	}
+	return opts.DirtyMark + "." + dr.DirtyHash[:abbrev]
+}
+
+// brokenSuffix returns the BrokenMark suffix if *DescribeResults are both
+// Broken and FormatOptions has a nonzero BrokenMark.
+func brokenSuffix(dr *DescribeResults, opts FormatOptions) string {
+	if dr.Broken && opts.BrokenMark != "" {
+		return opts.BrokenMark
+	}
 	return ""
 }
 
@@ -133,8 +178,8 @@ func dirtySuffix(dr *DescribeResults, opts FormatOptions) string {
 // 2) Abbrev=0 (effectively what --short would be if git describe had resonable
 // UX) and Long are incompatible options. If we get them, let Long win since
 // Abbrev=0 was probably a lazy zero value.
-func effectiveAbbrev(dr *DescribeResults, opts FormatOptions) uint {
-	if hashStrLen := uint(len(dr.HashStr)); hashStrLen < opts.Abbrev {
+func effectiveAbbrev(hashStr string, opts FormatOptions) uint {
+	if hashStrLen := uint(len(hashStr)); hashStrLen < opts.Abbrev {
 		return hashStrLen
 	}
 	if opts.Abbrev == 0 && opts.Long {
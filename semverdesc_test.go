@@ -138,6 +138,84 @@ var testCases = []struct {
 		want:   "v0.1.2+0.g71dd507.dirty",
 		legacy: "v0.1.2-0-g71dd507.dirty",
 	},
+	{
+		name: "dirty with dirty hash",
+		desc: DescribeResults{
+			TagName:   "v1.2.3",
+			Distance:  4,
+			HashStr:   "abc1234072d51458a534ca7e0ec7c181d8475477",
+			Dirty:     true,
+			DirtyHash: "d3adbeef5a1c",
+		},
+		opts: FormatOptions{
+			Abbrev:          7,
+			DirtyMark:       ".dirty",
+			DirtyHashAbbrev: 6,
+		},
+		want:   "v1.2.3+4.gabc1234.dirty.d3adbe",
+		legacy: "v1.2.3-4-gabc1234.dirty.d3adbe",
+	},
+	{
+		name: "dirty hash without dirtymark",
+		desc: DescribeResults{
+			TagName:   "v1.2.3",
+			Distance:  4,
+			HashStr:   "abc1234072d51458a534ca7e0ec7c181d8475477",
+			Dirty:     true,
+			DirtyHash: "d3adbeef5a1c",
+		},
+		opts: FormatOptions{
+			Abbrev:          7,
+			DirtyHashAbbrev: 6,
+		},
+		want:   "v1.2.3+4.gabc1234",
+		legacy: "v1.2.3-4-gabc1234",
+	},
+	{
+		name: "dirty without dirty hash",
+		desc: DescribeResults{
+			TagName:  "v1.2.3",
+			Distance: 0,
+			HashStr:  "abc1234072d51458a534ca7e0ec7c181d8475477",
+			Dirty:    true,
+		},
+		opts: FormatOptions{
+			DirtyMark:       "-dirty",
+			DirtyHashAbbrev: 6,
+		},
+		want:   "v1.2.3-dirty",
+		legacy: "v1.2.3-dirty",
+	},
+	{
+		name: "broken with brokenmark",
+		desc: DescribeResults{
+			TagName:  "v0.1.2",
+			Distance: 3,
+			HashStr:  "71dd5072d51458a534ca7e0ec7c181d84754774d",
+			Broken:   true,
+		},
+		opts: FormatOptions{
+			Abbrev:     7,
+			DirtyMark:  ".dirty",
+			BrokenMark: ".broken",
+		},
+		want:   "v0.1.2+3.g71dd507.broken",
+		legacy: "v0.1.2-3-g71dd507.broken",
+	},
+	{
+		name: "exact broken match without brokenmark",
+		desc: DescribeResults{
+			TagName:  "v0.1.2",
+			Distance: 0,
+			HashStr:  "71dd5072d51458a534ca7e0ec7c181d84754774d",
+			Broken:   true,
+		},
+		opts: FormatOptions{
+			DirtyMark: "-dirty",
+		},
+		want:   "v0.1.2",
+		legacy: "v0.1.2",
+	},
 }
 
 func TestDescribeResults_Format(t *testing.T) {
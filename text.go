@@ -0,0 +1,93 @@
+package semverdesc
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// regex to match the long semver describe format, with an optional dirty mark
+// (and dirty hash) or broken mark
+var semverLongRegex = regexp.MustCompile(`^(.+)\+(\d+)\.g([0-9a-f]+)(?:[-.]dirty(?:\.([0-9a-f]+))?()|[-.]broken())?$`)
+
+// regex to find text which looks like it is in the long semver describe format
+var semverLongLikeRegex = regexp.MustCompile(`\+\d+\.g`)
+
+// Marks used by MarshalText.
+const (
+	textDirtyMark  = "-dirty"
+	textBrokenMark = "-broken"
+)
+
+// MarshalText implements encoding.TextMarshaler, encoding the results in
+// semver describe format with the full HashStr and DirtyHash, along with a
+// "-dirty" or "-broken" mark, so that UnmarshalText restores them. The long
+// format is used whenever there is a HashStr, even for an exact match, as a
+// short format mark is indistinguishable from part of the tag name.
+//
+// Results with a Distance but no HashStr can't be encoded, as the format only
+// carries the distance along with the hash.
+func (dr *DescribeResults) MarshalText() ([]byte, error) {
+	if dr.Distance > 0 && dr.HashStr == "" {
+		return nil, errors.New("can not encode a distance without a hash: " + dr.TagName)
+	}
+	opts := FormatOptions{
+		Abbrev:          uint(len(dr.HashStr)),
+		Long:            dr.HashStr != "",
+		DirtyMark:       textDirtyMark,
+		DirtyHashAbbrev: uint(len(dr.DirtyHash)),
+		BrokenMark:      textBrokenMark,
+	}
+	return []byte(dr.Format(opts)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding results in
+// semver describe format.
+//
+// Text in the long format ("v1.2.3+4.gabc1234") sets the TagName, Distance and
+// (abbreviated) HashStr. It is marked Dirty if followed by a "-dirty" or
+// ".dirty" mark, along with the DirtyHash if one follows that (e.g.
+// "-dirty.d3adbe"), or Broken if followed by a "-broken" or ".broken" mark.
+// Text which looks like the long format but has any other suffix is an error.
+//
+// Any other text is taken to be an exact match of the TagName alone, as a
+// short format mark is indistinguishable from part of it.
+func (dr *DescribeResults) UnmarshalText(text []byte) error {
+	s := string(text)
+	if s == "" {
+		return errors.New("empty semver describe")
+	}
+	match := semverLongRegex.FindStringSubmatchIndex(s)
+	if match == nil {
+		if semverLongLikeRegex.MatchString(s) {
+			return errors.New("could not parse semver describe: " + s)
+		}
+		*dr = DescribeResults{TagName: s}
+		return nil
+	}
+	group := func(i int) string {
+		if match[2*i] < 0 {
+			return ""
+		}
+		return s[match[2*i]:match[2*i+1]]
+	}
+	distance, err := strconv.ParseUint(group(2), 10, 0)
+	if err != nil {
+		return errors.New("could not parse distance: " + group(2))
+	}
+	*dr = DescribeResults{
+		TagName:   group(1),
+		Distance:  uint(distance),
+		HashStr:   group(3),
+		Dirty:     match[10] >= 0,
+		DirtyHash: group(4),
+		Broken:    match[12] >= 0,
+	}
+	return nil
+}
+
+// Set implements flag.Value, setting the results from text in semver describe
+// format as UnmarshalText does.
+func (dr *DescribeResults) Set(s string) error {
+	return dr.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,206 @@
+package semverdesc
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = &DescribeResults{}
+	_ encoding.TextUnmarshaler = &DescribeResults{}
+	_ flag.Value               = &DescribeResults{}
+)
+
+func TestDescribeResults_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    DescribeResults
+		wantErr bool
+	}{
+		{
+			name: "exact match",
+			text: "v1.2.3",
+			want: DescribeResults{TagName: "v1.2.3"},
+		},
+		{
+			name: "prerelease exact match",
+			text: "v1.2.3-rc.1",
+			want: DescribeResults{TagName: "v1.2.3-rc.1"},
+		},
+		{
+			name: "long format",
+			text: "v1.2.3-rc.1+4.gabc1234",
+			want: DescribeResults{TagName: "v1.2.3-rc.1", Distance: 4, HashStr: "abc1234"},
+		},
+		{
+			name: "long format dirty",
+			text: "v1.2.3+4.gabc1234-dirty",
+			want: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "abc1234", Dirty: true},
+		},
+		{
+			name: "long format dot dirty",
+			text: "v1.2.3+4.gabc1234.dirty",
+			want: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "abc1234", Dirty: true},
+		},
+		{
+			name: "long format dirty hash",
+			text: "v1.2.3+4.gabc1234.dirty.d3adbe",
+			want: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "abc1234", Dirty: true, DirtyHash: "d3adbe"},
+		},
+		{
+			name: "long format broken",
+			text: "v1.2.3+4.gabc1234-broken",
+			want: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "abc1234", Broken: true},
+		},
+		{
+			name: "long format exact match",
+			text: "v1.2.3+0.gabc1234",
+			want: DescribeResults{TagName: "v1.2.3", HashStr: "abc1234"},
+		},
+		{
+			name:    "long format unknown mark",
+			text:    "v1.2.3+4.gabc1234-modified",
+			wantErr: true,
+		},
+		{
+			name: "tag with build metadata",
+			text: "v1.2.3+meta+4.gabc1234",
+			want: DescribeResults{TagName: "v1.2.3+meta", Distance: 4, HashStr: "abc1234"},
+		},
+		{
+			name:    "empty",
+			text:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got DescribeResults
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalText() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeResults_textRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		desc DescribeResults
+		text string
+	}{
+		{
+			name: "exact match",
+			desc: DescribeResults{TagName: "v1.2.3"},
+			text: "v1.2.3",
+		},
+		{
+			name: "exact match with hash",
+			desc: DescribeResults{TagName: "v1.2.3", HashStr: "d71dd5072d51458a534ca7e0ec7c181d84754774"},
+			text: "v1.2.3+0.gd71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		{
+			name: "long",
+			desc: DescribeResults{TagName: "v1.2.3-rc.1", Distance: 4, HashStr: "d71dd5072d51458a534ca7e0ec7c181d84754774"},
+			text: "v1.2.3-rc.1+4.gd71dd5072d51458a534ca7e0ec7c181d84754774",
+		},
+		{
+			name: "dirty",
+			desc: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "d71dd50", Dirty: true},
+			text: "v1.2.3+4.gd71dd50-dirty",
+		},
+		{
+			name: "dirty exact match",
+			desc: DescribeResults{TagName: "v1.2.3", HashStr: "d71dd50", Dirty: true},
+			text: "v1.2.3+0.gd71dd50-dirty",
+		},
+		{
+			name: "dirty hash",
+			desc: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "d71dd50", Dirty: true, DirtyHash: "d3adbeef5a1c"},
+			text: "v1.2.3+4.gd71dd50-dirty.d3adbeef5a1c",
+		},
+		{
+			name: "broken",
+			desc: DescribeResults{TagName: "v1.2.3", Distance: 4, HashStr: "d71dd50", Broken: true},
+			text: "v1.2.3+4.gd71dd50-broken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.desc.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.text {
+				t.Errorf("MarshalText() = %v, want %v", string(text), tt.text)
+			}
+			var got DescribeResults
+			if err := got.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.desc) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.desc)
+			}
+		})
+	}
+}
+
+func TestDescribeResults_MarshalText_distanceWithoutHash(t *testing.T) {
+	d := DescribeResults{TagName: "v1.2.3", Distance: 4}
+	if text, err := d.MarshalText(); err == nil {
+		t.Errorf("MarshalText() = %q, want error", text)
+	}
+}
+
+func TestDescribeResults_Set(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var d DescribeResults
+	fs.Var(&d, "version", "the version")
+	if err := fs.Parse([]string{"--version", "v0.2.1+15.gd71dd50"}); err != nil {
+		t.Fatal(err)
+	}
+	want := DescribeResults{TagName: "v0.2.1", Distance: 15, HashStr: "d71dd50"}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Set() = %+v, want %+v", d, want)
+	}
+}
+
+func TestDescribeResults_UnmarshalJSON(t *testing.T) {
+	want := DescribeResults{
+		TagName:      "v1.2.3",
+		Distance:     4,
+		HashStr:      "abc1234072d51458a534ca7e0ec7c181d8475477",
+		Dirty:        true,
+		Alternatives: []string{"v1.2.3-rc.1"},
+	}
+	encoded, err := json.Marshal(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got DescribeResults
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json round trip = %+v, want %+v", got, want)
+	}
+
+	var config struct {
+		Version DescribeResults `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(`{"version":"v1.2.3+4.gabc1234"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Version.String(); got != "v1.2.3+4.gabc1234" {
+		t.Errorf("json string = %v, want %v", got, "v1.2.3+4.gabc1234")
+	}
+}